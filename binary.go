@@ -0,0 +1,40 @@
+package rrule
+
+import (
+	"strings"
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler, the RRule is encoded as its RFC string,
+// without its runtime-only options, ex. ROption.DSTPolicy and ROption.IncludeDTStart.
+func (r *RRule) MarshalBinary() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (r *RRule) UnmarshalBinary(data []byte) error {
+	result, err := StrToRRule(string(data))
+	if err != nil {
+		return err
+	}
+	*r = *result
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, the Set is encoded as its recurrence lines.
+func (set *Set) MarshalBinary() ([]byte, error) {
+	return []byte(strings.Join(set.Recurrence(), "\n")), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (set *Set) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*set = Set{}
+		return nil
+	}
+	result, err := StrToRRuleSet(string(data))
+	if err != nil {
+		return err
+	}
+	*set = *result
+	return nil
+}
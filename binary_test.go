@@ -0,0 +1,74 @@
+package rrule
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+)
+
+func TestRRuleBinary(t *testing.T) {
+	str := "FREQ=WEEKLY;DTSTART=20120201T093000Z;COUNT=3;BYDAY=MO,FR"
+	r, _ := StrToRRule(str)
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	value := RRule{}
+	if err = value.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary(%s) returned error: %v", data, err)
+	}
+	if value.String() != str {
+		t.Errorf("get %v, want %v", value.String(), str)
+	}
+	if !timesEqual(value.All(), r.All()) {
+		t.Errorf("get %v, want %v", value.All(), r.All())
+	}
+}
+
+func TestSetBinary(t *testing.T) {
+	setStr := "DTSTART;TZID=America/New_York:20220101T090000\n" +
+		"RRULE:FREQ=DAILY;COUNT=5\n" +
+		"RDATE:20220110T140000Z\n" +
+		"EXRULE:FREQ=DAILY;DTSTART=20220102T140000Z;COUNT=1\n" +
+		"EXDATE:20220103T140000Z\n" +
+		"X-WR-TIMEZONE:America/New_York"
+	set, _ := StrToRRuleSet(setStr)
+	data, err := set.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	value := Set{}
+	if err = value.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary(%s) returned error: %v", data, err)
+	}
+	if !reflect.DeepEqual(value.Recurrence(), set.Recurrence()) {
+		t.Errorf("get %v, want %v", value.Recurrence(), set.Recurrence())
+	}
+}
+
+func TestSetGob(t *testing.T) {
+	set, _ := StrToRRuleSet("RRULE:FREQ=DAILY;DTSTART=20220101T090000Z;COUNT=3\nEXDATE:20220102T090000Z")
+	buf := bytes.Buffer{}
+	if err := gob.NewEncoder(&buf).Encode(set); err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+	value := Set{}
+	if err := gob.NewDecoder(&buf).Decode(&value); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if !timesEqual(value.All(), set.All()) {
+		t.Errorf("get %v, want %v", value.All(), set.All())
+	}
+}
+
+func TestSetBinaryEmpty(t *testing.T) {
+	data, _ := (&Set{}).MarshalBinary()
+	value := Set{}
+	if err := value.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary(%s) returned error: %v", data, err)
+	}
+	if len(value.Recurrence()) != 0 {
+		t.Errorf("get %v, want empty", value.Recurrence())
+	}
+}
@@ -0,0 +1,143 @@
+package rrule
+
+import (
+	"time"
+)
+
+// Builder builds an ROption with chained calls, ex.
+//
+//	option, err := NewBuilder(WEEKLY).Interval(2).Byweekday(MO, FR).Until(t).Build()
+//
+// Each method sets the ROption field of the same name.
+type Builder struct {
+	option ROption
+}
+
+// NewBuilder returns a Builder for an option with the given frequency.
+func NewBuilder(freq Frequency) *Builder {
+	return &Builder{option: ROption{Freq: freq}}
+}
+
+// Dtstart sets ROption.Dtstart.
+func (b *Builder) Dtstart(dtstart time.Time) *Builder {
+	b.option.Dtstart = dtstart
+	return b
+}
+
+// Interval sets ROption.Interval.
+func (b *Builder) Interval(interval int) *Builder {
+	b.option.Interval = interval
+	return b
+}
+
+// Wkst sets ROption.Wkst.
+func (b *Builder) Wkst(wkst Weekday) *Builder {
+	b.option.Wkst = wkst
+	b.option.wkstSet = true
+	return b
+}
+
+// Count sets ROption.Count.
+func (b *Builder) Count(count int) *Builder {
+	b.option.Count = count
+	return b
+}
+
+// Until sets ROption.Until.
+func (b *Builder) Until(until time.Time) *Builder {
+	b.option.Until = until
+	return b
+}
+
+// Bysetpos sets ROption.Bysetpos.
+func (b *Builder) Bysetpos(bysetpos ...int) *Builder {
+	b.option.Bysetpos = bysetpos
+	return b
+}
+
+// Bymonth sets ROption.Bymonth.
+func (b *Builder) Bymonth(bymonth ...int) *Builder {
+	b.option.Bymonth = bymonth
+	return b
+}
+
+// Bymonthday sets ROption.Bymonthday.
+func (b *Builder) Bymonthday(bymonthday ...int) *Builder {
+	b.option.Bymonthday = bymonthday
+	return b
+}
+
+// Byyearday sets ROption.Byyearday.
+func (b *Builder) Byyearday(byyearday ...int) *Builder {
+	b.option.Byyearday = byyearday
+	return b
+}
+
+// Byweekno sets ROption.Byweekno.
+func (b *Builder) Byweekno(byweekno ...int) *Builder {
+	b.option.Byweekno = byweekno
+	return b
+}
+
+// Byweekday sets ROption.Byweekday.
+func (b *Builder) Byweekday(byweekday ...Weekday) *Builder {
+	b.option.Byweekday = byweekday
+	return b
+}
+
+// Byhour sets ROption.Byhour.
+func (b *Builder) Byhour(byhour ...int) *Builder {
+	b.option.Byhour = byhour
+	return b
+}
+
+// Byminute sets ROption.Byminute.
+func (b *Builder) Byminute(byminute ...int) *Builder {
+	b.option.Byminute = byminute
+	return b
+}
+
+// Bysecond sets ROption.Bysecond.
+func (b *Builder) Bysecond(bysecond ...int) *Builder {
+	b.option.Bysecond = bysecond
+	return b
+}
+
+// Byeaster sets ROption.Byeaster.
+func (b *Builder) Byeaster(byeaster ...int) *Builder {
+	b.option.Byeaster = byeaster
+	return b
+}
+
+// Skip sets ROption.Skip.
+func (b *Builder) Skip(skip Skip) *Builder {
+	b.option.Skip = skip
+	return b
+}
+
+// DSTPolicy sets ROption.DSTPolicy.
+func (b *Builder) DSTPolicy(policy DSTPolicy) *Builder {
+	b.option.DSTPolicy = policy
+	return b
+}
+
+// IncludeDTStart sets ROption.IncludeDTStart.
+func (b *Builder) IncludeDTStart(include bool) *Builder {
+	b.option.IncludeDTStart = include
+	return b
+}
+
+// AllDay sets ROption.AllDay.
+func (b *Builder) AllDay(allDay bool) *Builder {
+	b.option.AllDay = allDay
+	return b
+}
+
+// Build returns a copy of the built option, checked with ROption.Validate.
+func (b *Builder) Build() (ROption, error) {
+	option := b.option.Clone()
+	if err := option.Validate(); err != nil {
+		return ROption{}, err
+	}
+	return option, nil
+}
@@ -0,0 +1,48 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuilder(t *testing.T) {
+	option, err := NewBuilder(WEEKLY).
+		Dtstart(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)).
+		Interval(2).
+		Byweekday(MO, FR).
+		Until(time.Date(1997, 9, 30, 9, 0, 0, 0, time.UTC)).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	want := ROption{Freq: WEEKLY,
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		Interval:  2,
+		Byweekday: []Weekday{MO, FR},
+		Until:     time.Date(1997, 9, 30, 9, 0, 0, 0, time.UTC)}
+	if !option.equals(&want) {
+		t.Errorf("get %v, want %v", option.String(), want.String())
+	}
+}
+
+func TestBuilderAllFields(t *testing.T) {
+	option, err := NewBuilder(YEARLY).Wkst(SU).Count(3).Bysetpos(-1).Bymonth(3).
+		Bymonthday(1).Byyearday(60).Byweekno(9).Byhour(9).Byminute(30).Bysecond(0).
+		Byeaster(0).Skip(SkipForward).DSTPolicy(DSTSkip).Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	want := "FREQ=YEARLY;WKST=SU;COUNT=3;BYSETPOS=-1;BYMONTH=3;BYMONTHDAY=1;BYYEARDAY=60;BYWEEKNO=9;BYHOUR=9;BYMINUTE=30;BYSECOND=0;BYEASTER=0;SKIP=FORWARD"
+	if value := option.String(); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if option.DSTPolicy != DSTSkip {
+		t.Errorf("get %v, want %v", option.DSTPolicy, DSTSkip)
+	}
+}
+
+func TestBuilderInvalid(t *testing.T) {
+	if _, err := NewBuilder(MONTHLY).Bymonthday(32).Build(); err == nil {
+		t.Errorf("Build() = nil error, want error")
+	}
+}
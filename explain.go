@@ -0,0 +1,110 @@
+package rrule
+
+import (
+	"fmt"
+	"time"
+)
+
+// Explain tells whether t is an occurrence of the RRule, and if not, which part excludes it,
+// ex. "excluded: BYMONTHDAY does not contain 15" or "included".
+// The parts of an excluded time are checked in turn: DTSTART and UNTIL, the INTERVAL phase,
+// the BY* parts, then BYSETPOS and COUNT. BY* parts defaulting to DTSTART, ex. the day of month
+// of a MONTHLY rule, are reported as such. It's meant to debug rules.
+func (r *RRule) Explain(t time.Time) string {
+	t = t.In(r.dtstart.Location()).Truncate(time.Second)
+	if r.Contains(t) {
+		return "included"
+	}
+	if reason := r.excludedBy(t); reason != "" {
+		return "excluded: " + reason
+	}
+	if len(r.bysetpos) != 0 && timeContains(r.DebugExpand(t), t) {
+		return fmt.Sprintf("excluded: BYSETPOS %v does not select it in its %s period", r.bysetpos, r.freq)
+	}
+	if r.count != 0 {
+		return fmt.Sprintf("excluded: it comes after the COUNT=%d occurrences", r.count)
+	}
+	return "excluded: no occurrence at this time"
+}
+
+// excludedBy returns why t, in the DTSTART zone, is not an occurrence of the RRule
+// according to its bounds, INTERVAL and BY* parts, or "" if none of them excludes it.
+func (r *RRule) excludedBy(t time.Time) string {
+	option := &r.OrigOptions
+	if t.Before(r.dtstart) {
+		return fmt.Sprintf("before DTSTART %s", timeToStr(r.dtstart))
+	}
+	if !r.until.IsZero() && t.After(r.until) {
+		return fmt.Sprintf("after UNTIL %s", timeToStr(r.until))
+	}
+	if periods := r.periodsBetween(r.dtstart, t); periods%r.interval != 0 {
+		return fmt.Sprintf("its %s period is not a multiple of INTERVAL=%d after the one of DTSTART",
+			r.freq, r.interval)
+	}
+	year, month, day := t.Date()
+	info := iterInfo{rrule: r}
+	info.rebuild(year, month)
+	i := t.YearDay() - 1
+	switch {
+	case len(r.bymonth) != 0 && !contains(r.bymonth, int(month)):
+		return partName("BYMONTH", len(option.Bymonth) != 0) + fmt.Sprintf(" does not contain %d", month)
+	case len(r.byweekno) != 0 && info.wnomask[i] == 0:
+		return fmt.Sprintf("BYWEEKNO %v does not contain its week", r.byweekno)
+	case len(r.byyearday) != 0 && !contains(r.byyearday, i+1) && !contains(r.byyearday, i-info.yearlen):
+		return fmt.Sprintf("BYYEARDAY does not contain %d or %d", i+1, i-info.yearlen)
+	case (len(r.bymonthday) != 0 || len(r.bynmonthday) != 0) &&
+		!contains(r.bymonthday, day) && !contains(r.bynmonthday, day-daysIn(month, year)-1):
+		return partName("BYMONTHDAY", len(option.Bymonthday) != 0) +
+			fmt.Sprintf(" does not contain %d or %d", day, day-daysIn(month, year)-1)
+	case len(r.byweekday) != 0 && !contains(r.byweekday, toPyWeekday(t.Weekday())):
+		return partName("BYDAY", len(option.Byweekday) != 0) +
+			fmt.Sprintf(" does not contain %v", Weekday{weekday: toPyWeekday(t.Weekday())})
+	case len(r.bynweekday) != 0 && info.nwdaymask[i] == 0:
+		return fmt.Sprintf("BYDAY %v does not match it as an nth weekday of its %s period", option.Byweekday, r.freq)
+	case len(r.byeaster) != 0 && info.eastermask[i] == 0:
+		return fmt.Sprintf("BYEASTER %v does not contain its offset from Easter", r.byeaster)
+	case len(r.byhour) != 0 && !contains(r.byhour, t.Hour()):
+		return partName("BYHOUR", len(option.Byhour) != 0) + fmt.Sprintf(" does not contain %d", t.Hour())
+	case len(r.byminute) != 0 && !contains(r.byminute, t.Minute()):
+		return partName("BYMINUTE", len(option.Byminute) != 0) + fmt.Sprintf(" does not contain %d", t.Minute())
+	case len(r.bysecond) != 0 && !contains(r.bysecond, t.Second()):
+		return partName("BYSECOND", len(option.Bysecond) != 0) + fmt.Sprintf(" does not contain %d", t.Second())
+	}
+	return ""
+}
+
+// partName returns name, marked as defaulting to DTSTART if the option doesn't set it.
+func partName(name string, set bool) string {
+	if !set {
+		return name + " (from DTSTART)"
+	}
+	return name
+}
+
+// periodsBetween returns the number of periods of the RRule frequency from the one of a
+// to the one of b, counted on the wall clock of their zone.
+func (r *RRule) periodsBetween(a, b time.Time) int {
+	switch r.freq {
+	case YEARLY:
+		return b.Year() - a.Year()
+	case MONTHLY:
+		return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+	case WEEKLY:
+		// count from the week starts on or before a and b
+		weekStart := func(t time.Time) time.Time {
+			return t.AddDate(0, 0, -pymod(toPyWeekday(t.Weekday())-r.wkst, 7))
+		}
+		return daysBetween(weekStart(a), weekStart(b)) / 7
+	}
+	periods := daysBetween(a, b)
+	if r.freq >= HOURLY {
+		periods = periods*24 + b.Hour() - a.Hour()
+	}
+	if r.freq >= MINUTELY {
+		periods = periods*60 + b.Minute() - a.Minute()
+	}
+	if r.freq >= SECONDLY {
+		periods = periods*60 + b.Second() - a.Second()
+	}
+	return periods
+}
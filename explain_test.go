@@ -0,0 +1,49 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExplain(t *testing.T) {
+	dtstart := time.Date(2022, 1, 3, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		option ROption
+		t      time.Time
+		want   string
+	}{
+		{ROption{Freq: MONTHLY, Bymonthday: []int{1, 10}},
+			time.Date(2022, 3, 10, 9, 0, 0, 0, time.UTC), "included"},
+		{ROption{Freq: MONTHLY, Bymonthday: []int{1, 10}},
+			time.Date(2022, 3, 15, 9, 0, 0, 0, time.UTC), "excluded: BYMONTHDAY does not contain 15 or -17"},
+		{ROption{Freq: MONTHLY},
+			time.Date(2022, 3, 15, 9, 0, 0, 0, time.UTC), "excluded: BYMONTHDAY (from DTSTART) does not contain 15 or -17"},
+		{ROption{Freq: MONTHLY, Bymonth: []int{1, 2}},
+			time.Date(2022, 3, 3, 9, 0, 0, 0, time.UTC), "excluded: BYMONTH does not contain 3"},
+		{ROption{Freq: WEEKLY, Byweekday: []Weekday{MO, WE}},
+			time.Date(2022, 3, 15, 9, 0, 0, 0, time.UTC), "excluded: BYDAY does not contain TU"},
+		{ROption{Freq: MONTHLY, Byweekday: []Weekday{TU.Nth(1)}},
+			time.Date(2022, 3, 15, 9, 0, 0, 0, time.UTC), "excluded: BYDAY [+1TU] does not match it as an nth weekday of its MONTHLY period"},
+		{ROption{Freq: DAILY},
+			time.Date(2022, 3, 15, 10, 0, 0, 0, time.UTC), "excluded: BYHOUR (from DTSTART) does not contain 10"},
+		{ROption{Freq: WEEKLY, Interval: 2},
+			time.Date(2022, 1, 10, 9, 0, 0, 0, time.UTC), "excluded: its WEEKLY period is not a multiple of INTERVAL=2 after the one of DTSTART"},
+		{ROption{Freq: DAILY},
+			time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC), "excluded: before DTSTART 20220103T090000Z"},
+		{ROption{Freq: DAILY, Until: time.Date(2022, 1, 5, 9, 0, 0, 0, time.UTC)},
+			time.Date(2022, 1, 6, 9, 0, 0, 0, time.UTC), "excluded: after UNTIL 20220105T090000Z"},
+		{ROption{Freq: DAILY, Count: 3},
+			time.Date(2022, 1, 6, 9, 0, 0, 0, time.UTC), "excluded: it comes after the COUNT=3 occurrences"},
+		{ROption{Freq: MONTHLY, Byweekday: []Weekday{MO, TU, WE, TH, FR}, Bysetpos: []int{-1}},
+			time.Date(2022, 3, 30, 9, 0, 0, 0, time.UTC), "excluded: BYSETPOS [-1] does not select it in its MONTHLY period"},
+		{ROption{Freq: MONTHLY, Byweekday: []Weekday{MO, TU, WE, TH, FR}, Bysetpos: []int{-1}},
+			time.Date(2022, 3, 31, 9, 0, 0, 0, time.UTC), "included"},
+	}
+	for _, c := range cases {
+		c.option.Dtstart = dtstart
+		r, _ := NewRRule(c.option)
+		if value := r.Explain(c.t); value != c.want {
+			t.Errorf("%v: Explain(%v) = %q, want %q", c.option.String(), c.t, value, c.want)
+		}
+	}
+}
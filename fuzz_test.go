@@ -0,0 +1,48 @@
+package rrule
+
+import (
+	"testing"
+)
+
+func FuzzStrToRRuleSet(f *testing.F) {
+	seeds := []string{
+		"RRULE:FREQ=WEEKLY;DTSTART=20120201T093000Z;INTERVAL=5;WKST=TU;COUNT=2;BYDAY=MO,+2FR",
+		"DTSTART;TZID=America/New_York:20220101T090000\nRRULE:FREQ=DAILY;COUNT=2",
+		"RRULE:FREQ=MONTHLY;COUNT=3;BYMONTHDAY=-1;BYSETPOS=1\nEXDATE:20220101T090000Z",
+		"RDATE;VALUE=DATE:20220101,20220102\nEXRULE:FREQ=YEARLY;COUNT=2;BYEASTER=0",
+		"RRULE:BYDAY=,",
+		"RRULE:INTERVAL=\x00",
+		"RRULE:FREQ=MONTHLY;BYDAY=-6MO;COUNT=3",
+		"RRULE:FREQ=YEARLY;BYDAY=+60MO;COUNT=3",
+		"RRULE:FREQ=YEARLY;BYMONTH=2;BYDAY=-5MO,+5FR;COUNT=3",
+		"RRULE:FREQ=MONTHLY;BYDAY=0MO;COUNT=3",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		set, err := StrToRRuleSet(s)
+		if err != nil {
+			return
+		}
+		_ = set.String()
+		// expand a bounded prefix of every rule and of the set, so that
+		// inputs which parse but break the iteration are caught too
+		for _, r := range append(set.GetRRule(), set.GetExRule()...) {
+			_ = r.Text()
+			fuzzExpand(r.Iterator())
+		}
+		fuzzExpand(set.Iterator())
+	})
+}
+
+// fuzzExpandLimit bounds the number of occurrences expanded for a fuzz input.
+const fuzzExpandLimit = 10
+
+func fuzzExpand(next Next) {
+	for i := 0; i < fuzzExpandLimit; i++ {
+		if _, ok := next(); !ok {
+			break
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package rrule
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Iterator iterates over the occurrences of an RRule, like RRule.Iterator,
+// and can save its position to resume it later, ex. after a restart.
+type Iterator struct {
+	rrule *RRule
+	next  Next
+	// count is the number of occurrences returned so far, last is the last of them
+	count int
+	last  time.Time
+}
+
+// NewIterator returns an Iterator over the occurrences of the RRule.
+func (r *RRule) NewIterator() *Iterator {
+	return &Iterator{rrule: r, next: r.Iterator()}
+}
+
+// Next returns the next occurrence, and false once the iterator is exhausted.
+func (it *Iterator) Next() (time.Time, bool) {
+	dt, ok := it.next()
+	if ok {
+		it.count++
+		it.last = dt
+	}
+	return dt, ok
+}
+
+// MarshalState returns an opaque cursor of the position of the iterator,
+// the number of occurrences returned so far and the last of them.
+func (it *Iterator) MarshalState() ([]byte, error) {
+	last, err := it.last.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(binary.AppendUvarint(nil, uint64(it.count)), last...), nil
+}
+
+// RestoreState moves the iterator to the position saved by MarshalState,
+// so that Next continues with the occurrence following the saved one.
+// Without COUNT the iteration resumes from the period before the saved occurrence,
+// see RRule.Contains, otherwise the occurrences before it are iterated again,
+// as COUNT depends on them. It returns an error if the saved occurrence is not
+// an occurrence of the rule, or with COUNT not the one at the saved position,
+// ex. because the rule changed.
+func (it *Iterator) RestoreState(state []byte) error {
+	count, n := binary.Uvarint(state)
+	if n <= 0 {
+		return errors.New("invalid iterator state")
+	}
+	var last time.Time
+	if err := last.UnmarshalBinary(state[n:]); err != nil {
+		return fmt.Errorf("invalid iterator state: %v", err)
+	}
+	if it.rrule.count == 0 && count != 0 {
+		next := it.rrule.skipTo(last).Iterator()
+		dt, ok := next()
+		for ok && dt.Before(last) {
+			dt, ok = next()
+		}
+		if !ok || !dt.Equal(last) {
+			return fmt.Errorf("iterator state at %v is not an occurrence", last)
+		}
+		it.next, it.count, it.last = next, int(count), dt
+		return nil
+	}
+	next := it.rrule.Iterator()
+	dt := time.Time{}
+	for i := uint64(0); i < count; i++ {
+		var ok bool
+		if dt, ok = next(); !ok {
+			return fmt.Errorf("iterator state after the last occurrence, at occurrence %d", count)
+		}
+	}
+	if !dt.Equal(last) {
+		return fmt.Errorf("iterator state at %v does not match occurrence %d %v", last, count, dt)
+	}
+	it.next, it.count, it.last = next, int(count), dt
+	return nil
+}
@@ -0,0 +1,77 @@
+package rrule
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestIteratorRestoreState(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: MONTHLY,
+		Byweekday: []Weekday{MO.Nth(1), FR.Nth(-1)},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{}
+	it := r.NewIterator()
+	for i := 0; i < 10; i++ {
+		dt, _ := it.Next()
+		want = append(want, dt)
+	}
+
+	value := []time.Time{}
+	it = r.NewIterator()
+	state, err := it.MarshalState()
+	for i := 0; i < 10; i++ {
+		if err != nil {
+			t.Fatalf("MarshalState returned error: %v", err)
+		}
+		// resume from the saved state with a new iterator each time
+		it = r.NewIterator()
+		if err := it.RestoreState(state); err != nil {
+			t.Fatalf("RestoreState returned error: %v", err)
+		}
+		dt, _ := it.Next()
+		value = append(value, dt)
+		state, err = it.MarshalState()
+	}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+
+	other, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 10, 0, 0, 0, time.UTC)})
+	if err := other.NewIterator().RestoreState(state); err == nil {
+		t.Errorf("RestoreState of another rule = nil error, want error")
+	}
+	other, _ = NewRRule(ROption{Freq: DAILY, Count: 20,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if err := other.NewIterator().RestoreState(state); err == nil {
+		t.Errorf("RestoreState of another rule with COUNT = nil error, want error")
+	}
+	if err := r.NewIterator().RestoreState([]byte{}); err == nil {
+		t.Errorf("RestoreState of empty state = nil error, want error")
+	}
+}
+
+func TestIteratorRestoreStateFarAway(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: HOURLY,
+		Interval: 5,
+		Dtstart:  time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	// the 175321st occurrence, 876600 hours after DTSTART, the ones before are not replayed
+	last := time.Date(2097, 9, 2, 9, 0, 0, 0, time.UTC)
+	if !r.Contains(last) {
+		t.Fatalf("%v is not an occurrence", last)
+	}
+	data, _ := last.MarshalBinary()
+	state := append(binary.AppendUvarint(nil, 175321), data...)
+	it := r.NewIterator()
+	if err := it.RestoreState(state); err != nil {
+		t.Fatalf("RestoreState returned error: %v", err)
+	}
+	want := last.Add(5 * time.Hour)
+	if value, ok := it.Next(); !ok || !value.Equal(want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if it.count != 175322 {
+		t.Errorf("get count %d, want %d", it.count, 175322)
+	}
+}
@@ -0,0 +1,159 @@
+package rrule
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type weekdayJSON struct {
+	Weekday string `json:"weekday"`
+	// N is nil if the weekday has no ordinal, or if it's in Weekday, ex. "+2MO"
+	N *int `json:"n,omitempty"`
+}
+
+// MarshalJSON encodes the weekday as an object, ex. {"weekday":"MO","n":1}.
+func (wday Weekday) MarshalJSON() ([]byte, error) {
+	aux := weekdayJSON{Weekday: Weekday{weekday: wday.weekday}.String()}
+	if wday.n != 0 {
+		aux.N = &wday.n
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes a weekday encoded by MarshalJSON.
+// The weekday may also carry its ordinal, ex. {"weekday":"+2MO"}.
+func (wday *Weekday) UnmarshalJSON(data []byte) error {
+	aux := weekdayJSON{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	result, err := strToWeekday(aux.Weekday)
+	if err != nil {
+		return err
+	}
+	if aux.N != nil {
+		result = result.Nth(*aux.N)
+	}
+	*wday = result
+	return nil
+}
+
+type roptionJSON struct {
+	Freq       string    `json:"freq"`
+	Dtstart    string    `json:"dtstart,omitempty"`
+	Tzid       string    `json:"tzid,omitempty"`
+	Interval   int       `json:"interval,omitempty"`
+	Wkst       *Weekday  `json:"wkst,omitempty"`
+	Count      int       `json:"count,omitempty"`
+	Until      string    `json:"until,omitempty"`
+	Bysetpos   []int     `json:"bysetpos,omitempty"`
+	Bymonth    []int     `json:"bymonth,omitempty"`
+	Bymonthday []int     `json:"bymonthday,omitempty"`
+	Byyearday  []int     `json:"byyearday,omitempty"`
+	Byweekno   []int     `json:"byweekno,omitempty"`
+	Byweekday  []Weekday `json:"byday,omitempty"`
+	Byhour     []int     `json:"byhour,omitempty"`
+	Byminute   []int     `json:"byminute,omitempty"`
+	Bysecond   []int     `json:"bysecond,omitempty"`
+	Byeaster   []int     `json:"byeaster,omitempty"`
+	Skip       string    `json:"skip,omitempty"`
+	AllDay     bool      `json:"allday,omitempty"`
+}
+
+func timeToJSON(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func jsonToTime(str string) (time.Time, error) {
+	if str == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, str)
+}
+
+// MarshalJSON encodes the option as a JSON object with named fields,
+// times are encoded as RFC 3339 strings, and the named time zone of DTSTART, if any, as its TZID.
+func (option ROption) MarshalJSON() ([]byte, error) {
+	aux := roptionJSON{
+		Freq:       option.Freq.String(),
+		Dtstart:    timeToJSON(option.Dtstart),
+		Tzid:       tzidOf(option.Dtstart),
+		Interval:   option.Interval,
+		Count:      option.Count,
+		Until:      timeToJSON(option.Until),
+		Bysetpos:   option.Bysetpos,
+		Bymonth:    option.Bymonth,
+		Bymonthday: option.Bymonthday,
+		Byyearday:  option.Byyearday,
+		Byweekno:   option.Byweekno,
+		Byweekday:  option.Byweekday,
+		Byhour:     option.Byhour,
+		Byminute:   option.Byminute,
+		Bysecond:   option.Bysecond,
+		Byeaster:   option.Byeaster,
+		AllDay:     option.AllDay,
+	}
+	if option.Skip != SkipOmit {
+		aux.Skip = option.Skip.String()
+	}
+	if option.Wkst != MO || option.wkstSet {
+		aux.Wkst = &option.Wkst
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes an option encoded by MarshalJSON.
+func (option *ROption) UnmarshalJSON(data []byte) error {
+	aux := roptionJSON{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	result := ROption{
+		Interval:   aux.Interval,
+		Count:      aux.Count,
+		Bysetpos:   aux.Bysetpos,
+		Bymonth:    aux.Bymonth,
+		Bymonthday: aux.Bymonthday,
+		Byyearday:  aux.Byyearday,
+		Byweekno:   aux.Byweekno,
+		Byweekday:  aux.Byweekday,
+		Byhour:     aux.Byhour,
+		Byminute:   aux.Byminute,
+		Bysecond:   aux.Bysecond,
+		Byeaster:   aux.Byeaster,
+		AllDay:     aux.AllDay,
+	}
+	var err error
+	if aux.Freq != "" {
+		if result.Freq, err = strToFreq(aux.Freq); err != nil {
+			return err
+		}
+	}
+	if aux.Skip != "" {
+		if result.Skip, err = strToSkip(aux.Skip); err != nil {
+			return err
+		}
+	}
+	if aux.Wkst != nil {
+		result.Wkst = *aux.Wkst
+		result.wkstSet = true
+	}
+	if result.Dtstart, err = jsonToTime(aux.Dtstart); err != nil {
+		return err
+	}
+	if aux.Tzid != "" {
+		loc, err := time.LoadLocation(aux.Tzid)
+		if err != nil {
+			return err
+		}
+		result.Dtstart = result.Dtstart.In(loc)
+	}
+	if result.Until, err = jsonToTime(aux.Until); err != nil {
+		return err
+	}
+	*option = result
+	return nil
+}
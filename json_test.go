@@ -0,0 +1,142 @@
+package rrule
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	str := "FREQ=WEEKLY;DTSTART=20120201T093000Z;INTERVAL=5;WKST=TU;COUNT=2;UNTIL=20130130T230000Z;BYSETPOS=2;BYMONTH=3;BYYEARDAY=95;BYWEEKNO=1;BYDAY=MO,+2FR;BYHOUR=9;BYMINUTE=30;BYSECOND=0;BYEASTER=-1;SKIP=FORWARD"
+	option, _ := StrToROption(str)
+	data, err := json.Marshal(option)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	value := ROption{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+	}
+	if s := value.String(); s != str {
+		t.Errorf("get %q, want %q", s, str)
+	}
+}
+
+func TestJSONFields(t *testing.T) {
+	option, _ := StrToROption("FREQ=MONTHLY;DTSTART=20120201T093000Z;BYDAY=-1FR,MO")
+	data, _ := json.Marshal(option)
+	want := `{"freq":"MONTHLY","dtstart":"2012-02-01T09:30:00Z","byday":[{"weekday":"FR","n":-1},{"weekday":"MO"}]}`
+	if string(data) != want {
+		t.Errorf("get %s, want %s", data, want)
+	}
+}
+
+func TestWeekdayJSONOrdinal(t *testing.T) {
+	value := Weekday{}
+	if err := json.Unmarshal([]byte(`{"weekday":"+2MO"}`), &value); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if want := MO.Nth(2); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	again := Weekday{}
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+	}
+	if again != value {
+		t.Errorf("get %v, want %v", again, value)
+	}
+}
+
+func TestJSONTZID(t *testing.T) {
+	str := "DTSTART;TZID=America/New_York:20120201T093000\nRRULE:FREQ=DAILY;COUNT=3"
+	option, _ := StrToROption(str)
+	data, err := json.Marshal(option)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	value := ROption{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+	}
+	if s := value.String(); s != str {
+		t.Errorf("get %q, want %q", s, str)
+	}
+	if err = json.Unmarshal([]byte(`{"freq":"DAILY","dtstart":"2012-02-01T09:30:00Z","tzid":"Nowhere/Nothing"}`), &value); err == nil {
+		t.Errorf("json.Unmarshal with an unknown TZID = nil, want error")
+	}
+}
+
+func TestJSONWkstMO(t *testing.T) {
+	str := "FREQ=WEEKLY;DTSTART=20120201T093000Z;WKST=MO;COUNT=2"
+	option, _ := StrToROption(str)
+	data, err := json.Marshal(option)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	value := ROption{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+	}
+	if s := value.String(); s != str {
+		t.Errorf("get %q, want %q", s, str)
+	}
+}
+
+func TestJSONEmpty(t *testing.T) {
+	data, err := json.Marshal(ROption{})
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	value := ROption{Freq: DAILY, Count: 3}
+	if err = json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+	}
+	empty := ROption{}
+	if value.String() != empty.String() {
+		t.Errorf("get %q, want %q", value.String(), empty.String())
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	cases := []string{
+		`{"freq":"HELLO"}`,
+		`{"freq":"DAILY","dtstart":"20120201"}`,
+		`{"freq":"DAILY","byday":[{"weekday":"XX"}]}`,
+		`{"freq":"DAILY","interval":"2"}`,
+	}
+	for _, item := range cases {
+		value := ROption{}
+		if err := json.Unmarshal([]byte(item), &value); err == nil {
+			t.Errorf("json.Unmarshal(%s) = nil, want error", item)
+		}
+	}
+}
+
+func TestFrequencyJSON(t *testing.T) {
+	config := struct {
+		Freq Frequency `json:"freq"`
+	}{}
+	if err := json.Unmarshal([]byte(`{"freq":"WEEKLY"}`), &config); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if config.Freq != WEEKLY {
+		t.Errorf("get %v, want %v", config.Freq, WEEKLY)
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	if want := `{"freq":"WEEKLY"}`; string(data) != want {
+		t.Errorf("get %s, want %s", data, want)
+	}
+	if err := json.Unmarshal([]byte(`{"freq":"weekly"}`), &config); err == nil {
+		t.Errorf("json.Unmarshal of an undefined frequency = nil error, want error")
+	}
+	if _, err := json.Marshal(Frequency(42)); err == nil {
+		t.Errorf("json.Marshal(Frequency(42)) = nil error, want error")
+	}
+}
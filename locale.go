@@ -0,0 +1,112 @@
+package rrule
+
+import (
+	"fmt"
+	"time"
+)
+
+// Locale holds the words used by TextInLocale to describe a rule.
+// TextInLocale assembles the sentence from them, so that adding a language
+// only needs a new Locale. Empty words are skipped.
+type Locale interface {
+	// Every describes the frequency and interval, ex. "every day" or "every 2 weeks".
+	Every(freq Frequency, interval int) string
+	// EveryWeekday describes a daily or weekly rule on Monday to Friday, ex. "every weekday".
+	EveryWeekday() string
+	// OnWeekdays describes BYDAY=MO,TU,WE,TH,FR with an interval, ex. "on weekdays".
+	OnWeekdays() string
+	// Weekday returns the name of a day of the week, ex. "Monday".
+	Weekday(day time.Weekday) string
+	// Month returns the name of a month, ex. "January".
+	Month(month time.Month) string
+	// Ordinal returns "1st", "2nd", ... for positive n and "last", "2nd last", ... for negative n.
+	Ordinal(n int) string
+	// Day returns the noun of a day of the month, as in "the last day".
+	Day() string
+	// In, On, The and And are the connectors of "in January", "on the 1st Monday" and "a, b and c".
+	In() string
+	On() string
+	The() string
+	And() string
+	// Times describes COUNT, ex. "for 10 times".
+	Times(count int) string
+	// Until describes UNTIL, ex. "until December 31, 2022".
+	Until(t time.Time) string
+}
+
+// English is the Locale of Text.
+var English Locale = english{}
+
+type english struct{}
+
+var (
+	englishUnits    = [...]string{"year", "month", "week", "day", "hour", "minute", "second"}
+	englishWeekdays = [...]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+)
+
+func (english) Every(freq Frequency, interval int) string {
+	unit := "?"
+	if freq >= YEARLY && freq <= SECONDLY {
+		unit = englishUnits[freq]
+	}
+	if interval == 1 {
+		return "every " + unit
+	}
+	return fmt.Sprintf("every %d %ss", interval, unit)
+}
+
+func (english) EveryWeekday() string { return "every weekday" }
+
+func (english) OnWeekdays() string { return "on weekdays" }
+
+func (english) Weekday(day time.Weekday) string { return englishWeekdays[day] }
+
+func (english) Month(month time.Month) string { return month.String() }
+
+func (english) Ordinal(n int) string {
+	if n == -1 {
+		return "last"
+	}
+	if n < 0 {
+		return englishOrdinal(-n) + " last"
+	}
+	return englishOrdinal(n)
+}
+
+// englishOrdinal returns the English ordinal of a positive number, ex. 1st, 2nd, 11th.
+func englishOrdinal(n int) string {
+	suffix := "th"
+	switch n % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		suffix = "th"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+func (english) Day() string { return "day" }
+
+func (english) In() string { return "in" }
+
+func (english) On() string { return "on" }
+
+func (english) The() string { return "the" }
+
+func (english) And() string { return "and" }
+
+func (english) Times(count int) string {
+	if count == 1 {
+		return "for 1 time"
+	}
+	return fmt.Sprintf("for %d times", count)
+}
+
+func (english) Until(t time.Time) string {
+	return "until " + t.Format("January 2, 2006")
+}
@@ -0,0 +1,81 @@
+package rrule
+
+import (
+	"fmt"
+	"time"
+)
+
+// German is the Locale for German, ex. "alle 2 Wochen am Montag und Freitag".
+var German Locale = german{}
+
+type german struct{}
+
+var (
+	// each unit with its "every" form and its plural
+	germanUnits = [...][3]string{
+		{"jedes", "Jahr", "Jahre"},
+		{"jeden", "Monat", "Monate"},
+		{"jede", "Woche", "Wochen"},
+		{"jeden", "Tag", "Tage"},
+		{"jede", "Stunde", "Stunden"},
+		{"jede", "Minute", "Minuten"},
+		{"jede", "Sekunde", "Sekunden"},
+	}
+	germanWeekdays = [...]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"}
+	germanMonths   = [...]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+		"Juli", "August", "September", "Oktober", "November", "Dezember"}
+)
+
+func (german) Every(freq Frequency, interval int) string {
+	unit := [3]string{"jede", "?", "?"}
+	if freq >= YEARLY && freq <= SECONDLY {
+		unit = germanUnits[freq]
+	}
+	if interval == 1 {
+		return unit[0] + " " + unit[1]
+	}
+	return fmt.Sprintf("alle %d %s", interval, unit[2])
+}
+
+func (german) EveryWeekday() string { return "jeden Werktag" }
+
+func (german) OnWeekdays() string { return "an Werktagen" }
+
+func (german) Weekday(day time.Weekday) string { return germanWeekdays[day] }
+
+func (german) Month(month time.Month) string {
+	if month < time.January || month > time.December {
+		return month.String()
+	}
+	return germanMonths[month-1]
+}
+
+func (german) Ordinal(n int) string {
+	switch {
+	case n == -1:
+		return "letzten"
+	case n == -2:
+		return "vorletzten"
+	case n < 0:
+		return fmt.Sprintf("%d. letzten", -n)
+	}
+	return fmt.Sprintf("%d.", n)
+}
+
+func (german) Day() string { return "Tag" }
+
+func (german) In() string { return "im" }
+
+func (german) On() string { return "am" }
+
+func (german) The() string { return "" }
+
+func (german) And() string { return "und" }
+
+func (german) Times(count int) string {
+	return fmt.Sprintf("für %d Mal", count)
+}
+
+func (german) Until(t time.Time) string {
+	return fmt.Sprintf("bis %d. %s %d", t.Day(), germanMonths[t.Month()-1], t.Year())
+}
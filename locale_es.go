@@ -0,0 +1,84 @@
+package rrule
+
+import (
+	"fmt"
+	"time"
+)
+
+// Spanish is the Locale for Spanish, ex. "cada 2 semanas el lunes y viernes".
+var Spanish Locale = spanish{}
+
+type spanish struct{}
+
+var (
+	// each unit with its plural
+	spanishUnits = [...][2]string{
+		{"año", "años"},
+		{"mes", "meses"},
+		{"semana", "semanas"},
+		{"día", "días"},
+		{"hora", "horas"},
+		{"minuto", "minutos"},
+		{"segundo", "segundos"},
+	}
+	spanishWeekdays = [...]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"}
+	spanishMonths   = [...]string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+		"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+)
+
+func (spanish) Every(freq Frequency, interval int) string {
+	unit := [2]string{"?", "?"}
+	if freq >= YEARLY && freq <= SECONDLY {
+		unit = spanishUnits[freq]
+	}
+	if interval == 1 {
+		return "cada " + unit[0]
+	}
+	return fmt.Sprintf("cada %d %s", interval, unit[1])
+}
+
+func (spanish) EveryWeekday() string { return "cada día laborable" }
+
+func (spanish) OnWeekdays() string { return "en días laborables" }
+
+func (spanish) Weekday(day time.Weekday) string { return spanishWeekdays[day] }
+
+func (spanish) Month(month time.Month) string {
+	if month < time.January || month > time.December {
+		return month.String()
+	}
+	return spanishMonths[month-1]
+}
+
+func (spanish) Ordinal(n int) string {
+	switch {
+	case n == -1:
+		return "último"
+	case n == -2:
+		return "penúltimo"
+	case n < 0:
+		return fmt.Sprintf("%dº último", -n)
+	}
+	return fmt.Sprintf("%dº", n)
+}
+
+func (spanish) Day() string { return "día" }
+
+func (spanish) In() string { return "en" }
+
+func (spanish) On() string { return "el" }
+
+func (spanish) The() string { return "" }
+
+func (spanish) And() string { return "y" }
+
+func (spanish) Times(count int) string {
+	if count == 1 {
+		return "durante 1 vez"
+	}
+	return fmt.Sprintf("durante %d veces", count)
+}
+
+func (spanish) Until(t time.Time) string {
+	return fmt.Sprintf("hasta el %d de %s de %d", t.Day(), spanishMonths[t.Month()-1], t.Year())
+}
@@ -0,0 +1,45 @@
+package rrule
+
+import (
+	"fmt"
+	"time"
+)
+
+// Weekdays returns an RRule occurring every weekday, Monday to Friday,
+// at the time of dtstart, ex. "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR".
+func Weekdays(dtstart time.Time) *RRule {
+	return mustRRule(ROption{Freq: WEEKLY,
+		Byweekday: []Weekday{MO, TU, WE, TH, FR},
+		Dtstart:   dtstart})
+}
+
+// MonthlyNthWeekday returns an RRule occurring on the nth wd of each month, at the time of
+// dtstart, ex. MonthlyNthWeekday(dtstart, 1, MO) for "FREQ=MONTHLY;BYDAY=+1MO".
+// A negative n counts from the end of the month, ex. -1 for the last wd.
+// Months without an nth wd, ex. a fifth Monday, are skipped.
+// It returns an error unless n is between 1 and 5 or between -5 and -1.
+func MonthlyNthWeekday(dtstart time.Time, n int, wd Weekday) (*RRule, error) {
+	if n == 0 || n < -5 || n > 5 {
+		return nil, fmt.Errorf("invalid ordinal %d: must be between 1 and 5, or between -5 and -1", n)
+	}
+	return mustRRule(ROption{Freq: MONTHLY,
+		Byweekday: []Weekday{wd.Nth(n)},
+		Dtstart:   dtstart}), nil
+}
+
+// Quarterly returns an RRule occurring every three months from dtstart, on its day of month
+// and time, ex. "FREQ=MONTHLY;INTERVAL=3". Months without this day, ex. April 31, are skipped.
+func Quarterly(dtstart time.Time) *RRule {
+	return mustRRule(ROption{Freq: MONTHLY,
+		Interval: 3,
+		Dtstart:  dtstart})
+}
+
+// mustRRule returns the RRule of an option NewRRule can't reject.
+func mustRRule(option ROption) *RRule {
+	r, err := NewRRule(option)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
@@ -0,0 +1,53 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekdays(t *testing.T) {
+	// 1997-09-05 is a Friday
+	r := Weekdays(time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC))
+	want := []time.Time{time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC)}
+	if value, _ := r.AllWithLimit(3); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := r.String(); value != "FREQ=WEEKLY;DTSTART=19970905T090000Z;BYDAY=MO,TU,WE,TH,FR" {
+		t.Errorf("get %v", value)
+	}
+}
+
+func TestMonthlyNthWeekday(t *testing.T) {
+	r, _ := MonthlyNthWeekday(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC), 1, MO)
+	want := []time.Time{time.Date(1997, 10, 6, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 11, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 12, 1, 9, 0, 0, 0, time.UTC)}
+	if value, _ := r.AllWithLimit(3); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	r, _ = MonthlyNthWeekday(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC), -1, FR)
+	want = []time.Time{time.Date(1997, 9, 26, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 10, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 11, 28, 9, 0, 0, 0, time.UTC)}
+	if value, _ := r.AllWithLimit(3); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	for _, n := range []int{0, 6, -6} {
+		if _, err := MonthlyNthWeekday(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC), n, MO); err == nil {
+			t.Errorf("MonthlyNthWeekday(%d) succeeded, want error", n)
+		}
+	}
+}
+
+func TestQuarterly(t *testing.T) {
+	r := Quarterly(time.Date(1997, 11, 15, 9, 0, 0, 0, time.UTC))
+	want := []time.Time{time.Date(1997, 11, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(1998, 2, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(1998, 5, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(1998, 8, 15, 9, 0, 0, 0, time.UTC)}
+	if value, _ := r.AllWithLimit(4); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
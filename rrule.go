@@ -1,7 +1,9 @@
 package rrule
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"time"
 )
@@ -36,6 +38,8 @@ func init() {
 	}
 }
 
+var errUnbounded = errors.New("rrule is unbounded, it has neither COUNT nor UNTIL")
+
 // Frequency denotes the period on which the rule is evaluated.
 type Frequency int
 
@@ -50,6 +54,35 @@ const (
 	SECONDLY
 )
 
+// Skip denotes how invalid dates generated by the rule (ex. February 30) are handled, see RFC 7529.
+type Skip int
+
+// Skip constants
+const (
+	// SkipOmit omits invalid dates, it's the default
+	SkipOmit Skip = iota
+	// SkipBackward moves invalid dates back to the last valid day of the month
+	SkipBackward
+	// SkipForward moves invalid dates forward to the first day of the next month
+	SkipForward
+)
+
+// DSTPolicy denotes how local times which don't exist in the DTSTART zone,
+// as 02:30 on a day when clocks jump from 02:00 to 03:00, are handled.
+// Local times which exist twice, when clocks go back, always refer to the first of them.
+type DSTPolicy int
+
+// DSTPolicy constants
+const (
+	// DSTKeep keeps the time normalized by time.Date, it's the default
+	DSTKeep DSTPolicy = iota
+	// DSTSkip omits nonexistent times
+	DSTSkip
+	// DSTShiftForward moves nonexistent times forward by the length of the gap,
+	// ex. 02:30 becomes 03:30, as RFC 5545 specifies
+	DSTShiftForward
+)
+
 // Weekday specifying the nth weekday.
 // Field N could be positive or negative (like MO(+2) or MO(-3).
 // Not specifying N (0) is the same as specifying +1.
@@ -61,10 +94,40 @@ type Weekday struct {
 // Nth return the nth weekday
 // __call__ - Cannot call the object directly,
 // do it through e.g. TH.nth(-1) instead,
-func (wday *Weekday) Nth(n int) Weekday {
+func (wday Weekday) Nth(n int) Weekday {
 	return Weekday{wday.weekday, n}
 }
 
+// Day returns the day of the week of the weekday.
+func (wday Weekday) Day() time.Weekday {
+	return time.Weekday((wday.weekday + 1) % 7)
+}
+
+// N returns the ordinal of the weekday, ex. 2 for +2MO, -1 for -1FR, or 0 if it has none.
+func (wday Weekday) N() int {
+	return wday.n
+}
+
+// Next returns the first day strictly after from which is the weekday, at the time of from.
+// The ordinal of the weekday is ignored.
+func (wday Weekday) Next(from time.Time) time.Time {
+	days := pymod(int(wday.Day())-int(from.Weekday()), 7)
+	if days == 0 {
+		days = 7
+	}
+	return from.AddDate(0, 0, days)
+}
+
+// Previous returns the last day strictly before from which is the weekday, at the time of from.
+// The ordinal of the weekday is ignored.
+func (wday Weekday) Previous(from time.Time) time.Time {
+	days := pymod(int(from.Weekday())-int(wday.Day()), 7)
+	if days == 0 {
+		days = 7
+	}
+	return from.AddDate(0, 0, -days)
+}
+
 // Weekdays
 var (
 	MO = Weekday{weekday: 0}
@@ -94,10 +157,242 @@ type ROption struct {
 	Byminute   []int
 	Bysecond   []int
 	Byeaster   []int
+	Skip       Skip
+	// DSTPolicy is not part of the RFC string of the option
+	DSTPolicy DSTPolicy
+	// IncludeDTStart makes DTSTART the first occurrence even if it doesn't match the rule,
+	// as RFC 5545 requires, see NewRRule. It's a runtime-only setting, not part of the RFC string
+	// of the option either, so the SQL, binary and JSON encodings don't keep it.
+	IncludeDTStart bool
+	// AllDay marks DTSTART as a DATE, of an all-day recurrence: the RFC string of the option
+	// has DTSTART and UNTIL as dates, ex. "DTSTART=20220101;UNTIL=20221231".
+	// StrToROption sets it for a date DTSTART.
+	AllDay bool
+	// wkstSet tells that WKST was given explicitly, so that String keeps WKST=MO
+	wkstSet bool
+}
+
+// Validate checks the BY* parts of the option against the ranges allowed by RFC 5545,
+// that BYSETPOS comes with another BY* part, that INTERVAL is not negative
+// (0 stands for the default 1), and that COUNT and UNTIL are not both set.
+// The returned error names the first offending part and value.
+func (option *ROption) Validate() error {
+	if option.Interval < 0 {
+		return fmt.Errorf("invalid INTERVAL value %d: must be positive", option.Interval)
+	}
+	if option.Count != 0 && !option.Until.IsZero() {
+		return errors.New("COUNT and UNTIL must not both be set")
+	}
+	return option.validateParts()
+}
+
+// validateParts checks the BY* parts of the option against the ranges allowed by RFC 5545,
+// and that BYSETPOS comes with another BY* part.
+func (option *ROption) validateParts() error {
+	checks := []struct {
+		name   string
+		values []int
+		min    int
+		max    int
+		signed bool
+	}{
+		{"BYSETPOS", option.Bysetpos, 1, 366, true},
+		{"BYMONTH", option.Bymonth, 1, 12, false},
+		{"BYMONTHDAY", option.Bymonthday, 1, 31, true},
+		{"BYYEARDAY", option.Byyearday, 1, 366, true},
+		{"BYWEEKNO", option.Byweekno, 1, 53, true},
+		{"BYHOUR", option.Byhour, 0, 23, false},
+		{"BYMINUTE", option.Byminute, 0, 59, false},
+		{"BYSECOND", option.Bysecond, 0, 59, false},
+		{"BYEASTER", option.Byeaster, -366, 366, false},
+	}
+	for _, check := range checks {
+		for _, v := range check.values {
+			abs := v
+			if check.signed && v < 0 {
+				abs = -v
+			}
+			if abs < check.min || abs > check.max {
+				if check.signed {
+					return fmt.Errorf("invalid %s value %d: must be between %d and %d, or between %d and %d",
+						check.name, v, check.min, check.max, -check.max, -check.min)
+				}
+				return fmt.Errorf("invalid %s value %d: must be between %d and %d",
+					check.name, v, check.min, check.max)
+			}
+		}
+	}
+	// an ordinal BYDAY counts the weekdays of the month for MONTHLY, of the year otherwise
+	maxN := 53
+	if option.Freq == MONTHLY {
+		maxN = 5
+	}
+	for _, wday := range option.Byweekday {
+		if wday.n < -maxN || wday.n > maxN {
+			return fmt.Errorf("invalid BYDAY value %v: ordinal must be between 1 and %d, or between %d and -1",
+				wday, maxN, -maxN)
+		}
+	}
+	if len(option.Bysetpos) != 0 &&
+		len(option.Bymonth) == 0 && len(option.Bymonthday) == 0 &&
+		len(option.Byyearday) == 0 && len(option.Byweekno) == 0 &&
+		len(option.Byweekday) == 0 && len(option.Byhour) == 0 &&
+		len(option.Byminute) == 0 && len(option.Bysecond) == 0 &&
+		len(option.Byeaster) == 0 {
+		return errors.New("invalid BYSETPOS: must be used with another BY* part")
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the option, sharing no slice with it.
+func (option *ROption) Clone() ROption {
+	result := *option
+	result.Bysetpos = append([]int(nil), option.Bysetpos...)
+	result.Bymonth = append([]int(nil), option.Bymonth...)
+	result.Bymonthday = append([]int(nil), option.Bymonthday...)
+	result.Byyearday = append([]int(nil), option.Byyearday...)
+	result.Byweekno = append([]int(nil), option.Byweekno...)
+	result.Byweekday = append([]Weekday(nil), option.Byweekday...)
+	result.Byhour = append([]int(nil), option.Byhour...)
+	result.Byminute = append([]int(nil), option.Byminute...)
+	result.Bysecond = append([]int(nil), option.Bysecond...)
+	result.Byeaster = append([]int(nil), option.Byeaster...)
+	return result
+}
+
+// RoundDTStart rounds DTSTART to the nearest second in place, where NewRRule
+// and String would otherwise drop its fraction of second.
+func (option *ROption) RoundDTStart() {
+	option.Dtstart = option.Dtstart.Round(time.Second)
+}
+
+// Normalize canonicalizes the option in place: BY* lists are sorted and
+// deduplicated (into new slices, shared ones are not modified), DTSTART is
+// truncated to seconds as NewRRule does, and an INTERVAL of 1 is reset to
+// its zero value, which means the same.
+func (option *ROption) Normalize() {
+	if option.Interval == 1 {
+		option.Interval = 0
+	}
+	option.Dtstart = option.Dtstart.Truncate(time.Second)
+	option.Bysetpos = uniqueSorted(option.Bysetpos)
+	option.Bymonth = uniqueSorted(option.Bymonth)
+	option.Bymonthday = uniqueSorted(option.Bymonthday)
+	option.Byyearday = uniqueSorted(option.Byyearday)
+	option.Byweekno = uniqueSorted(option.Byweekno)
+	option.Byhour = uniqueSorted(option.Byhour)
+	option.Byminute = uniqueSorted(option.Byminute)
+	option.Bysecond = uniqueSorted(option.Bysecond)
+	option.Byeaster = uniqueSorted(option.Byeaster)
+	if len(option.Byweekday) != 0 {
+		wdays := []Weekday{}
+		for _, wday := range option.Byweekday {
+			if !weekdayContains(wdays, wday) {
+				wdays = append(wdays, wday)
+			}
+		}
+		sort.Slice(wdays, func(i, j int) bool {
+			if wdays[i].n != wdays[j].n {
+				return wdays[i].n < wdays[j].n
+			}
+			return wdays[i].weekday < wdays[j].weekday
+		})
+		option.Byweekday = wdays
+	}
+}
+
+// Minimize returns a copy of the option without the parts that don't change its occurrences.
+// It's conservative, only these parts are dropped:
+//   - INTERVAL=1, the default
+//   - WKST=MO, the default, and any WKST if the rule is neither WEEKLY nor has BYWEEKNO
+//   - a single BYDAY of a WEEKLY rule, without ordinal, on the weekday of DTSTART
+//   - a single BYMONTHDAY of a MONTHLY rule on the day of DTSTART
+//   - a single BYMONTH and BYMONTHDAY of a YEARLY rule on the month and day of DTSTART
+//   - a single BYHOUR, BYMINUTE or BYSECOND on the time of DTSTART, if the rule is less frequent
+//
+// BYDAY and BYMONTHDAY are only dropped if no other BYWEEKNO, BYYEARDAY, BYMONTHDAY, BYDAY
+// or BYEASTER part is set, as they default to DTSTART only then. Without DTSTART,
+// only INTERVAL and WKST are dropped.
+func (option *ROption) Minimize() ROption {
+	result := option.Clone()
+	if result.Interval == 1 {
+		result.Interval = 0
+	}
+	if result.Wkst == MO || result.Freq != WEEKLY && len(result.Byweekno) == 0 {
+		result.Wkst = MO
+		result.wkstSet = false
+	}
+	if result.Dtstart.IsZero() {
+		return result
+	}
+	// DTSTART as NewRRule uses it
+	dtstart := result.Dtstart.Truncate(time.Second)
+	if result.AllDay {
+		dtstart = time.Date(dtstart.Year(), dtstart.Month(), dtstart.Day(), 0, 0, 0, 0, dtstart.Location())
+	}
+	onlyDtstart := func(values []int, value int) bool {
+		return len(values) == 1 && values[0] == value
+	}
+	if len(result.Byweekno) == 0 && len(result.Byyearday) == 0 && len(result.Byeaster) == 0 {
+		switch {
+		case result.Freq == WEEKLY && len(result.Bymonthday) == 0 && len(result.Byweekday) == 1 &&
+			result.Byweekday[0] == Weekday{weekday: toPyWeekday(dtstart.Weekday())}:
+			result.Byweekday = nil
+		case result.Freq == MONTHLY && len(result.Byweekday) == 0 &&
+			onlyDtstart(result.Bymonthday, dtstart.Day()):
+			result.Bymonthday = nil
+		case result.Freq == YEARLY && len(result.Byweekday) == 0 &&
+			onlyDtstart(result.Bymonth, int(dtstart.Month())) && onlyDtstart(result.Bymonthday, dtstart.Day()):
+			result.Bymonth = nil
+			result.Bymonthday = nil
+		}
+	}
+	if result.Freq < HOURLY && onlyDtstart(result.Byhour, dtstart.Hour()) {
+		result.Byhour = nil
+	}
+	if result.Freq < MINUTELY && onlyDtstart(result.Byminute, dtstart.Minute()) {
+		result.Byminute = nil
+	}
+	if result.Freq < SECONDLY && onlyDtstart(result.Bysecond, dtstart.Second()) {
+		result.Bysecond = nil
+	}
+	return result
+}
+
+// equals reports whether two normalized options are the same.
+func (option *ROption) equals(other *ROption) bool {
+	if option.Freq != other.Freq ||
+		!option.Dtstart.Equal(other.Dtstart) ||
+		option.Interval != other.Interval ||
+		option.Wkst != other.Wkst ||
+		option.Count != other.Count ||
+		!option.Until.Equal(other.Until) ||
+		option.Skip != other.Skip ||
+		option.DSTPolicy != other.DSTPolicy ||
+		option.IncludeDTStart != other.IncludeDTStart ||
+		option.AllDay != other.AllDay ||
+		len(option.Byweekday) != len(other.Byweekday) {
+		return false
+	}
+	for i := range option.Byweekday {
+		if option.Byweekday[i] != other.Byweekday[i] {
+			return false
+		}
+	}
+	return intsEqual(option.Bysetpos, other.Bysetpos) &&
+		intsEqual(option.Bymonth, other.Bymonth) &&
+		intsEqual(option.Bymonthday, other.Bymonthday) &&
+		intsEqual(option.Byyearday, other.Byyearday) &&
+		intsEqual(option.Byweekno, other.Byweekno) &&
+		intsEqual(option.Byhour, other.Byhour) &&
+		intsEqual(option.Byminute, other.Byminute) &&
+		intsEqual(option.Bysecond, other.Bysecond) &&
+		intsEqual(option.Byeaster, other.Byeaster)
 }
 
 // RRule offers a small, complete, and very fast, implementation of the recurrence rules
 // documented in the iCalendar RFC, including support for caching of results.
+// An RRule is safe for concurrent use by multiple goroutines.
 type RRule struct {
 	OrigOptions             ROption
 	freq                    Frequency
@@ -117,21 +412,45 @@ type RRule struct {
 	byminute                []int
 	bysecond                []int
 	byeaster                []int
+	skip                    Skip
+	dstPolicy               DSTPolicy
+	includeDTStart          bool
 	timeset                 []time.Time
-	len                     int
 }
 
-// NewRRule construct a new RRule instance
+// NewRRule construct a new RRule instance.
+// The option is not checked against RFC 5545 ranges, call ROption.Validate
+// beforehand to reject out of range BY* values.
+// If both COUNT and UNTIL are set, the occurrences stop at whichever is reached first.
+// UNTIL is compared with the occurrences as an instant, it may be in another zone than DTSTART.
+// As in python-dateutil, DTSTART is an occurrence only if it matches the rule, ex. a Tuesday
+// DTSTART is not an occurrence of FREQ=WEEKLY;BYDAY=MO. RFC 5545 makes DTSTART the first
+// occurrence in any case, counted by COUNT, set ROption.IncludeDTStart to follow it.
+// Without DTSTART the rule starts at the current time, see NewRRuleFrom to anchor it.
+// DTSTART is truncated to the second, as RFC strings can't hold a fraction of second,
+// call ROption.RoundDTStart beforehand to round it instead.
+// The DTSTART of an AllDay option is moved to the midnight of its day, so the occurrences are days.
+// The weeks of BYWEEKNO start on WKST, week 1 being the first of them with at least 4 days
+// in the year, so it may start in the previous year. With the default WKST=MO they are ISO 8601 weeks,
+// with WKST=SU week 1 is the Sunday to Saturday week containing at least January 1 to 4.
 func NewRRule(arg ROption) (*RRule, error) {
 	r := RRule{}
-	r.OrigOptions = arg
+	// copy the BY* slices, so that changes to arg don't leak into the rule and vice versa
+	arg = arg.Clone()
+	r.OrigOptions = arg.Clone()
 	if arg.Dtstart.IsZero() {
 		arg.Dtstart = time.Now()
 	}
 	arg.Dtstart = arg.Dtstart.Truncate(time.Second)
+	if arg.AllDay {
+		year, month, day := arg.Dtstart.Date()
+		arg.Dtstart = time.Date(year, month, day, 0, 0, 0, 0, arg.Dtstart.Location())
+	}
 	r.dtstart = arg.Dtstart
 	r.freq = arg.Freq
-	if arg.Interval == 0 {
+	if arg.Interval < 0 {
+		return nil, errors.New("interval must be positive")
+	} else if arg.Interval == 0 {
 		r.interval = 1
 	} else {
 		r.interval = arg.Interval
@@ -165,6 +484,9 @@ func NewRRule(arg ROption) (*RRule, error) {
 	r.bymonth = arg.Bymonth
 	r.byyearday = arg.Byyearday
 	r.byeaster = arg.Byeaster
+	r.skip = arg.Skip
+	r.dstPolicy = arg.DSTPolicy
+	r.includeDTStart = arg.IncludeDTStart
 	for _, mday := range arg.Bymonthday {
 		if mday > 0 {
 			r.bymonthday = append(r.bymonthday, mday)
@@ -214,6 +536,20 @@ func NewRRule(arg ROption) (*RRule, error) {
 	return &r, nil
 }
 
+// NewRRuleFrom is like NewRRule, with dtstart, ex. the start of the enclosing event,
+// as DTSTART if the option has none. It's the reference time of a rule without DTSTART,
+// in place of the current time, so the rule doesn't depend on when it's built.
+// It returns an error if neither the option nor dtstart give a DTSTART.
+func NewRRuleFrom(arg ROption, dtstart time.Time) (*RRule, error) {
+	if arg.Dtstart.IsZero() {
+		if dtstart.IsZero() {
+			return nil, errors.New("rrule has no DTSTART")
+		}
+		arg.Dtstart = dtstart
+	}
+	return NewRRule(arg)
+}
+
 type iterInfo struct {
 	rrule       *RRule
 	lastyear    int
@@ -234,7 +570,7 @@ type iterInfo struct {
 
 func (info *iterInfo) rebuild(year int, month time.Month) {
 	// Every mask is 7 days longer to handle cross-year weekly periods.
-	if year != info.lastyear {
+	if year != info.lastyear || info.wdaymask == nil {
 		info.yearlen = 365 + isLeap(year)
 		info.nextyearlen = 365 + isLeap(year+1)
 		info.firstyday = time.Date(
@@ -294,9 +630,19 @@ func (info *iterInfo) rebuild(year int, month time.Month) {
 					}
 				}
 			}
-			if contains(info.rrule.byweekno, 1) {
+			// Week number 1 of next year is also week number -numweeks of next year
+			nyearweekday := pymod(info.yearweekday+info.yearlen, 7)
+			nno1wkst := pymod(7-nyearweekday+info.rrule.wkst, 7)
+			var nwyearlen int
+			if nno1wkst >= 4 {
+				nwyearlen = info.nextyearlen + pymod(nyearweekday-info.rrule.wkst, 7)
+			} else {
+				nwyearlen = info.nextyearlen - nno1wkst
+			}
+			ndiv, nmod := divmod(nwyearlen, 7)
+			nnumweeks := ndiv + nmod/4
+			if contains(info.rrule.byweekno, 1) || contains(info.rrule.byweekno, -nnumweeks) {
 				// Check week number 1 of next year as well
-				// TODO: Check -numweeks for next year.
 				i := no1wkst + numweeks*7
 				if no1wkst != firstwkst {
 					i -= 7 - firstwkst
@@ -360,21 +706,17 @@ func (info *iterInfo) rebuild(year int, month time.Month) {
 		if len(ranges) != 0 {
 			// Weekly frequency won't get here, so we may not
 			// care about cross-year weekly periods.
-			info.nwdaymask = make([]int, info.yearlen)
+			if len(info.nwdaymask) == info.yearlen {
+				// reuse the mask of the previous month, MONTHLY rules rebuild it every month
+				for i := range info.nwdaymask {
+					info.nwdaymask[i] = 0
+				}
+			} else {
+				info.nwdaymask = make([]int, info.yearlen)
+			}
 			for _, x := range ranges {
-				first, last := x[0], x[1]
-				last--
-				for _, y := range info.rrule.bynweekday {
-					wday, n := y.weekday, y.n
-					var i int
-					if n < 0 {
-						i = last + (n+1)*7
-						i -= pymod(info.wdaymask[i]-wday, 7)
-					} else {
-						i = first + (n-1)*7
-						i += pymod(7-info.wdaymask[i]+wday, 7)
-					}
-					if first <= i && i <= last {
+				for _, wday := range info.rrule.bynweekday {
+					if i, ok := info.nthWeekday(x[0], x[1], wday); ok {
 						info.nwdaymask[i] = 1
 					}
 				}
@@ -385,22 +727,156 @@ func (info *iterInfo) rebuild(year int, month time.Month) {
 		info.eastermask = make([]int, info.yearlen+7)
 		eyday := easter(year).YearDay() - 1
 		for _, offset := range info.rrule.byeaster {
-			info.eastermask[eyday+offset] = 1
+			// offsets reaching into another year match no day of this one
+			if i := eyday + offset; i >= 0 && i < info.yearlen {
+				info.eastermask[i] = 1
+			}
 		}
 	}
 	info.lastyear = year
 	info.lastmonth = month
 }
 
+// nthWeekday returns the day of year of the nth weekday wday, ex. 2TU or -1FR,
+// of the days of year from first to end excluded, and false if there is none.
+func (info *iterInfo) nthWeekday(first, end int, wday Weekday) (int, bool) {
+	last := end - 1
+	// the first or last day of the nth week of days, out of the range for a too large ordinal
+	var i int
+	if wday.n < 0 {
+		if i = last + (wday.n+1)*7; i < first {
+			return 0, false
+		}
+		i -= pymod(info.wdaymask[i]-wday.weekday, 7)
+	} else {
+		if i = first + (wday.n-1)*7; i > last {
+			return 0, false
+		}
+		i += pymod(7-info.wdaymask[i]+wday.weekday, 7)
+	}
+	return i, first <= i && i <= last
+}
+
+// nthWeekdaysOnly reports whether the days of the rule are only constrained by ordinal
+// weekdays, ex. BYDAY=2TU, and BYMONTH, for a MONTHLY rule.
+func (info *iterInfo) nthWeekdaysOnly() bool {
+	r := info.rrule
+	return r.freq == MONTHLY && len(r.bynweekday) != 0 && len(r.byweekday) == 0 &&
+		len(r.byweekno) == 0 && len(r.byyearday) == 0 && len(r.bymonthday) == 0 &&
+		len(r.bynmonthday) == 0 && len(r.byeaster) == 0
+}
+
+// nthWeekdaySet is getdayset for a MONTHLY rule satisfying nthWeekdaysOnly:
+// the days of the ordinal weekdays of month are computed instead of filtered out of the month.
+func (info *iterInfo) nthWeekdaySet(month time.Month) ([]*int, int, int) {
+	set := make([]*int, info.yearlen)
+	start, end := info.mrange[month-1], info.mrange[month]
+	if len(info.rrule.bymonth) != 0 && !contains(info.rrule.bymonth, int(month)) {
+		return set, start, end
+	}
+	days := make([]int, len(info.rrule.bynweekday))
+	for j, wday := range info.rrule.bynweekday {
+		if i, ok := info.nthWeekday(start, end, wday); ok {
+			days[j] = i
+			set[i] = &days[j]
+		}
+	}
+	return set, start, end
+}
+
+// dayFiltered reports whether the day of year i is excluded by the BY* parts of the rule.
+// BYMONTH and BYMONTHDAY are only checked if dates is true.
+func (info *iterInfo) dayFiltered(i int, dates bool) bool {
+	r := info.rrule
+	return dates && len(r.bymonth) != 0 && !contains(r.bymonth, info.mmask[i]) ||
+		len(r.byweekno) != 0 && info.wnomask[i] == 0 ||
+		len(r.byweekday) != 0 && !contains(r.byweekday, info.wdaymask[i]) ||
+		len(info.nwdaymask) != 0 && info.nwdaymask[i] == 0 ||
+		len(r.byeaster) != 0 && info.eastermask[i] == 0 ||
+		dates && (len(r.bymonthday) != 0 || len(r.bynmonthday) != 0) &&
+			!contains(r.bymonthday, info.mdaymask[i]) &&
+			!contains(r.bynmonthday, info.nmdaymask[i]) ||
+		len(r.byyearday) != 0 &&
+			(i < info.yearlen &&
+				!contains(r.byyearday, i+1) &&
+				!contains(r.byyearday, -info.yearlen+i) ||
+				i >= info.yearlen &&
+					!contains(r.byyearday, i+1-info.yearlen) &&
+					!contains(r.byyearday, -info.nextyearlen+i-info.yearlen))
+}
+
+// skipDays returns the days of year substituting the invalid dates generated
+// by BYMONTHDAY (ex. February 30) in the current period, according to the SKIP part.
+// SkipBackward moves them to the last day of the month, or of the previous month
+// for negative month days, SkipForward to the first day of the next month,
+// or of the month for negative month days.
+func (info *iterInfo) skipDays(month time.Month) (result []int) {
+	r := info.rrule
+	months := []int{int(month)}
+	if r.freq == YEARLY {
+		months = r.bymonth
+		if len(months) == 0 {
+			months = rang(1, 13)
+		}
+	}
+	for _, m := range months {
+		first, last := info.mrange[m-1], info.mrange[m]
+		daysinmonth := last - first
+		for _, mday := range r.bymonthday {
+			if mday > daysinmonth {
+				if r.skip == SkipBackward {
+					result = append(result, last-1)
+				} else {
+					result = append(result, last)
+				}
+			}
+		}
+		for _, mday := range r.bynmonthday {
+			if -mday > daysinmonth {
+				if r.skip == SkipBackward {
+					result = append(result, first-1)
+				} else {
+					result = append(result, first)
+				}
+			}
+		}
+	}
+	return
+}
+
 func (info *iterInfo) getdayset(freq Frequency, year int, month time.Month, day int) ([]*int, int, int) {
 	switch freq {
 	case YEARLY:
 		set := make([]*int, info.yearlen)
-		for i := 0; i < info.yearlen; i++ {
-			temp := i
-			set[i] = &temp
+		days := make([]int, info.yearlen)
+		if len(info.rrule.bymonth) == 0 {
+			for i := range days {
+				days[i] = i
+				set[i] = &days[i]
+			}
+			return set, 0, info.yearlen
+		}
+		// only the days of the BYMONTH months, instead of filtering out the others
+		start, end := info.yearlen, 0
+		for _, month := range info.rrule.bymonth {
+			if month < 1 || month > 12 {
+				continue
+			}
+			for i := info.mrange[month-1]; i < info.mrange[month]; i++ {
+				days[i] = i
+				set[i] = &days[i]
+			}
+			if info.mrange[month-1] < start {
+				start = info.mrange[month-1]
+			}
+			if info.mrange[month] > end {
+				end = info.mrange[month]
+			}
+		}
+		if end < start {
+			return set, 0, 0
 		}
-		return set, 0, info.yearlen
+		return set, start, end
 	case MONTHLY:
 		set := make([]*int, info.yearlen)
 		start, end := info.mrange[month-1], info.mrange[month]
@@ -468,37 +944,87 @@ type rIterator struct {
 	total    int
 	count    int
 	remain   []time.Time
+	last     time.Time
 	finished bool
+	// ctx stops the generation, checked once per period, when it's done
+	ctx context.Context
+}
+
+// localTime combines the day of date with the time of day of timeTemp,
+// in the location of timeTemp, applying the DST policy of the RRule.
+// It returns false if the time doesn't exist and the policy is DSTSkip.
+func (r *RRule) localTime(date, timeTemp time.Time) (time.Time, bool) {
+	res := time.Date(date.Year(), date.Month(), date.Day(),
+		timeTemp.Hour(), timeTemp.Minute(), timeTemp.Second(),
+		timeTemp.Nanosecond(), timeTemp.Location())
+	if r.dstPolicy == DSTKeep {
+		return res, true
+	}
+	wall := time.Date(date.Year(), date.Month(), date.Day(),
+		timeTemp.Hour(), timeTemp.Minute(), timeTemp.Second(),
+		timeTemp.Nanosecond(), time.UTC)
+	resWall := time.Date(res.Year(), res.Month(), res.Day(),
+		res.Hour(), res.Minute(), res.Second(), res.Nanosecond(), time.UTC)
+	if resWall.Equal(wall) {
+		return res, true
+	}
+	if r.dstPolicy == DSTSkip {
+		return time.Time{}, false
+	}
+	// time.Date normalized the time to one side of the gap,
+	// interpret it with the offset in effect before the gap
+	start, end := res.ZoneBounds()
+	gap := start
+	if resWall.Before(wall) {
+		gap = end
+	}
+	_, offset := gap.Add(-time.Nanosecond).Zone()
+	return wall.Add(-time.Duration(offset) * time.Second).In(res.Location()), true
+}
+
+// dayset returns the days of year of the current period matching the BY* parts,
+// as getdayset, and whether some days were filtered out.
+func (iterator *rIterator) dayset() (dayset []*int, start, end int, filtered bool) {
+	r := iterator.ii.rrule
+	if iterator.ii.nthWeekdaysOnly() {
+		dayset, start, end = iterator.ii.nthWeekdaySet(iterator.month)
+		return dayset, start, end, true
+	}
+	// Get dayset with the right frequency
+	dayset, start, end = iterator.ii.getdayset(r.freq, iterator.year, iterator.month, iterator.day)
+
+	// Do the "hard" work ;-)
+	for _, i := range dayset[start:end] {
+		if i != nil && iterator.ii.dayFiltered(*i, true) {
+			dayset[*i] = nil
+			filtered = true
+		}
+	}
+	if r.skip != SkipOmit && (r.freq == YEARLY || r.freq == MONTHLY) {
+		for _, i := range iterator.ii.skipDays(iterator.month) {
+			if iterator.ii.dayFiltered(i, false) {
+				continue
+			}
+			temp := i
+			dayset[i] = &temp
+			if i < start {
+				start = i
+			} else if i >= end {
+				end = i + 1
+			}
+		}
+	}
+	return
 }
 
 func (iterator *rIterator) generate() {
 	r := iterator.ii.rrule
 	for len(iterator.remain) == 0 {
-		// Get dayset with the right frequency
-		dayset, start, end := iterator.ii.getdayset(r.freq, iterator.year, iterator.month, iterator.day)
-
-		// Do the "hard" work ;-)
-		filtered := false
-		for _, i := range dayset[start:end] {
-			if len(r.bymonth) != 0 && !contains(r.bymonth, iterator.ii.mmask[*i]) ||
-				len(r.byweekno) != 0 && iterator.ii.wnomask[*i] == 0 ||
-				len(r.byweekday) != 0 && !contains(r.byweekday, iterator.ii.wdaymask[*i]) ||
-				len(iterator.ii.nwdaymask) != 0 && iterator.ii.nwdaymask[*i] == 0 ||
-				len(r.byeaster) != 0 && iterator.ii.eastermask[*i] == 0 ||
-				(len(r.bymonthday) != 0 || len(r.bynmonthday) != 0) &&
-					!contains(r.bymonthday, iterator.ii.mdaymask[*i]) &&
-					!contains(r.bynmonthday, iterator.ii.nmdaymask[*i]) ||
-				len(r.byyearday) != 0 &&
-					(*i < iterator.ii.yearlen &&
-						!contains(r.byyearday, *i+1) &&
-						!contains(r.byyearday, -iterator.ii.yearlen+*i) ||
-						*i >= iterator.ii.yearlen &&
-							!contains(r.byyearday, *i+1-iterator.ii.yearlen) &&
-							!contains(r.byyearday, -iterator.ii.nextyearlen+*i-iterator.ii.yearlen)) {
-				dayset[*i] = nil
-				filtered = true
-			}
+		if iterator.ctx.Err() != nil {
+			iterator.finished = true
+			return
 		}
+		dayset, start, end, filtered := iterator.dayset()
 		// Output results
 		if len(r.bysetpos) != 0 && len(iterator.timeset) != 0 {
 			poslist := []time.Time{}
@@ -521,26 +1047,23 @@ func (iterator *rIterator) generate() {
 				}
 				timeTemp := iterator.timeset[timepos]
 				date := iterator.ii.firstyday.AddDate(0, 0, i)
-				res := time.Date(date.Year(), date.Month(), date.Day(),
-					timeTemp.Hour(), timeTemp.Minute(), timeTemp.Second(),
-					timeTemp.Nanosecond(), timeTemp.Location())
-				if !timeContains(poslist, res) {
+				res, ok := r.localTime(date, timeTemp)
+				if ok && !timeContains(poslist, res) {
 					poslist = append(poslist, res)
 				}
 			}
 			sort.Sort(timeSlice(poslist))
 			for _, res := range poslist {
 				if !r.until.IsZero() && res.After(r.until) {
-					r.len = iterator.total
 					iterator.finished = true
 					return
-				} else if !res.Before(r.dtstart) {
+				} else if !res.Before(r.dtstart) && (r.skip == SkipOmit || res.After(iterator.last)) {
+					iterator.last = res
 					iterator.total++
 					iterator.remain = append(iterator.remain, res)
 					if iterator.count != 0 {
 						iterator.count--
 						if iterator.count == 0 {
-							r.len = iterator.total
 							iterator.finished = true
 							return
 						}
@@ -554,20 +1077,20 @@ func (iterator *rIterator) generate() {
 				}
 				date := iterator.ii.firstyday.AddDate(0, 0, *i)
 				for _, timeTemp := range iterator.timeset {
-					res := time.Date(date.Year(), date.Month(), date.Day(),
-						timeTemp.Hour(), timeTemp.Minute(), timeTemp.Second(),
-						timeTemp.Nanosecond(), timeTemp.Location())
+					res, ok := r.localTime(date, timeTemp)
+					if !ok {
+						continue
+					}
 					if !r.until.IsZero() && res.After(r.until) {
-						r.len = iterator.total
 						iterator.finished = true
 						return
-					} else if !res.Before(r.dtstart) {
+					} else if !res.Before(r.dtstart) && (r.skip == SkipOmit || res.After(iterator.last)) {
+						iterator.last = res
 						iterator.total++
 						iterator.remain = append(iterator.remain, res)
 						if iterator.count != 0 {
 							iterator.count--
 							if iterator.count == 0 {
-								r.len = iterator.total
 								iterator.finished = true
 								return
 							}
@@ -576,18 +1099,22 @@ func (iterator *rIterator) generate() {
 				}
 			}
 		}
-		// Handle frequency and interval
+		// Handle frequency and interval,
+		// a zero interval, as in the zero RRule, would never advance
+		interval := r.interval
+		if interval <= 0 {
+			interval = 1
+		}
 		fixday := false
 		if r.freq == YEARLY {
-			iterator.year += r.interval
+			iterator.year += interval
 			if iterator.year > MAXYEAR {
-				r.len = iterator.total
 				iterator.finished = true
 				return
 			}
 			iterator.ii.rebuild(iterator.year, iterator.month)
 		} else if r.freq == MONTHLY {
-			iterator.month += time.Month(r.interval)
+			iterator.month += time.Month(interval)
 			if iterator.month > 12 {
 				div, mod := divmod(int(iterator.month), 12)
 				iterator.month = time.Month(mod)
@@ -597,7 +1124,6 @@ func (iterator *rIterator) generate() {
 					iterator.year--
 				}
 				if iterator.year > MAXYEAR {
-					r.len = iterator.total
 					iterator.finished = true
 					return
 				}
@@ -605,22 +1131,22 @@ func (iterator *rIterator) generate() {
 			iterator.ii.rebuild(iterator.year, iterator.month)
 		} else if r.freq == WEEKLY {
 			if r.wkst > iterator.weekday {
-				iterator.day += -(iterator.weekday + 1 + (6 - r.wkst)) + r.interval*7
+				iterator.day += -(iterator.weekday + 1 + (6 - r.wkst)) + interval*7
 			} else {
-				iterator.day += -(iterator.weekday - r.wkst) + r.interval*7
+				iterator.day += -(iterator.weekday - r.wkst) + interval*7
 			}
 			iterator.weekday = r.wkst
 			fixday = true
 		} else if r.freq == DAILY {
-			iterator.day += r.interval
+			iterator.day += interval
 			fixday = true
 		} else if r.freq == HOURLY {
 			if filtered {
 				// Jump to one iteration before next day
-				iterator.hour += ((23 - iterator.hour) / r.interval) * r.interval
+				iterator.hour += ((23 - iterator.hour) / interval) * interval
 			}
 			for {
-				iterator.hour += r.interval
+				iterator.hour += interval
 				div, mod := divmod(iterator.hour, 24)
 				if div != 0 {
 					iterator.hour = mod
@@ -635,10 +1161,10 @@ func (iterator *rIterator) generate() {
 		} else if r.freq == MINUTELY {
 			if filtered {
 				// Jump to one iteration before next day
-				iterator.minute += ((1439 - (iterator.hour*60 + iterator.minute)) / r.interval) * r.interval
+				iterator.minute += ((1439 - (iterator.hour*60 + iterator.minute)) / interval) * interval
 			}
 			for {
-				iterator.minute += r.interval
+				iterator.minute += interval
 				div, mod := divmod(iterator.minute, 60)
 				if div != 0 {
 					iterator.minute = mod
@@ -655,15 +1181,19 @@ func (iterator *rIterator) generate() {
 					(len(r.byminute) == 0 || contains(r.byminute, iterator.minute)) {
 					break
 				}
+				if len(r.byhour) != 0 && !contains(r.byhour, iterator.hour) {
+					// Jump to one iteration before next hour
+					iterator.minute += ((59 - iterator.minute) / interval) * interval
+				}
 			}
 			iterator.timeset = iterator.ii.gettimeset(r.freq, iterator.hour, iterator.minute, iterator.second)
 		} else if r.freq == SECONDLY {
 			if filtered {
 				// Jump to one iteration before next day
-				iterator.second += (((86399 - (iterator.hour*3600 + iterator.minute*60 + iterator.second)) / r.interval) * r.interval)
+				iterator.second += (((86399 - (iterator.hour*3600 + iterator.minute*60 + iterator.second)) / interval) * interval)
 			}
 			for {
-				iterator.second += r.interval
+				iterator.second += interval
 				div, mod := divmod(iterator.second, 60)
 				if div != 0 {
 					iterator.second = mod
@@ -685,6 +1215,13 @@ func (iterator *rIterator) generate() {
 					(len(r.bysecond) == 0 || contains(r.bysecond, iterator.second)) {
 					break
 				}
+				if len(r.byhour) != 0 && !contains(r.byhour, iterator.hour) {
+					// Jump to one iteration before next hour
+					iterator.second += ((3599 - (iterator.minute*60 + iterator.second)) / interval) * interval
+				} else if len(r.byminute) != 0 && !contains(r.byminute, iterator.minute) {
+					// Jump to one iteration before next minute
+					iterator.second += ((59 - iterator.second) / interval) * interval
+				}
 			}
 			iterator.timeset = iterator.ii.gettimeset(r.freq, iterator.hour, iterator.minute, iterator.second)
 		}
@@ -698,7 +1235,6 @@ func (iterator *rIterator) generate() {
 						iterator.month = 1
 						iterator.year++
 						if iterator.year > MAXYEAR {
-							r.len = iterator.total
 							iterator.finished = true
 							return
 						}
@@ -724,8 +1260,46 @@ func (iterator *rIterator) next() (time.Time, bool) {
 	return value, true
 }
 
-// Iterator return an iterator for RRule
+// Iterator return an iterator for RRule.
+// Occurrences are computed lazily, one period at a time, honoring COUNT and UNTIL.
+// The iterator keeps its state apart from the RRule, which is never modified once built,
+// so All, Between, Before, After and the other read methods are safe for concurrent use.
+// Once the iterator is exhausted every further call returns false.
 func (r *RRule) Iterator() Next {
+	return r.iteratorWithContext(context.Background())
+}
+
+// iteratorWithContext is like Iterator, the iterator ends when ctx is done.
+func (r *RRule) iteratorWithContext(ctx context.Context) Next {
+	iterator := r.newIterator()
+	iterator.ctx = ctx
+	if !r.includeDTStart || !r.until.IsZero() && r.dtstart.After(r.until) {
+		return iterator.next
+	}
+	first := r.newIterator()
+	first.ctx = ctx
+	if first, ok := first.next(); ok && first.Equal(r.dtstart) {
+		return iterator.next
+	}
+	// DTSTART doesn't match the rule, it comes first and counts as an occurrence
+	if r.count == 1 {
+		iterator.finished = true
+	} else if r.count > 1 {
+		iterator.count--
+	}
+	iterator.last = r.dtstart
+	started := false
+	return func() (time.Time, bool) {
+		if !started {
+			started = true
+			return r.dtstart, true
+		}
+		return iterator.next()
+	}
+}
+
+// newIterator returns an iterator of the occurrences of the RRule matching its parts.
+func (r *RRule) newIterator() *rIterator {
 	iterator := rIterator{}
 	iterator.year, iterator.month, iterator.day = r.dtstart.Date()
 	iterator.hour, iterator.minute, iterator.second = r.dtstart.Clock()
@@ -734,19 +1308,56 @@ func (r *RRule) Iterator() Next {
 	iterator.ii = iterInfo{rrule: r}
 	iterator.ii.rebuild(iterator.year, iterator.month)
 
+	iterator.timeset = iterator.firstTimeset()
+	iterator.count = r.count
+	iterator.ctx = context.Background()
+	return &iterator
+}
+
+// firstTimeset returns the times of day of the first period of the iterator.
+func (iterator *rIterator) firstTimeset() []time.Time {
+	r := iterator.ii.rrule
 	if r.freq < HOURLY {
-		iterator.timeset = r.timeset
-	} else {
-		if r.freq >= HOURLY && len(r.byhour) != 0 && !contains(r.byhour, iterator.hour) ||
-			r.freq >= MINUTELY && len(r.byminute) != 0 && !contains(r.byminute, iterator.minute) ||
-			r.freq >= SECONDLY && len(r.bysecond) != 0 && !contains(r.bysecond, iterator.second) {
-			iterator.timeset = []time.Time{}
-		} else {
-			iterator.timeset = iterator.ii.gettimeset(r.freq, iterator.hour, iterator.minute, iterator.second)
+		return r.timeset
+	}
+	if r.freq >= HOURLY && len(r.byhour) != 0 && !contains(r.byhour, iterator.hour) ||
+		r.freq >= MINUTELY && len(r.byminute) != 0 && !contains(r.byminute, iterator.minute) ||
+		r.freq >= SECONDLY && len(r.bysecond) != 0 && !contains(r.bysecond, iterator.second) {
+		return []time.Time{}
+	}
+	return iterator.ii.gettimeset(r.freq, iterator.hour, iterator.minute, iterator.second)
+}
+
+// DebugExpand returns the candidate occurrences of the period of the RRule containing periodStart,
+// ex. its month for a MONTHLY rule, before BYSETPOS picks among them.
+// DTSTART, COUNT and UNTIL are not applied. It's meant to debug rules, not to list occurrences.
+func (r *RRule) DebugExpand(periodStart time.Time) []time.Time {
+	periodStart = periodStart.In(r.dtstart.Location())
+	if r.freq == WEEKLY {
+		// the period starts on the week start on or before periodStart
+		periodStart = periodStart.AddDate(0, 0, -pymod(toPyWeekday(periodStart.Weekday())-r.wkst, 7))
+	}
+	iterator := rIterator{}
+	iterator.year, iterator.month, iterator.day = periodStart.Date()
+	iterator.hour, iterator.minute, iterator.second = periodStart.Clock()
+	iterator.ii = iterInfo{rrule: r}
+	iterator.ii.rebuild(iterator.year, iterator.month)
+	timeset := iterator.firstTimeset()
+
+	result := []time.Time{}
+	dayset, start, end, _ := iterator.dayset()
+	for _, i := range dayset[start:end] {
+		if i == nil {
+			continue
+		}
+		date := iterator.ii.firstyday.AddDate(0, 0, *i)
+		for _, timeTemp := range timeset {
+			if res, ok := r.localTime(date, timeTemp); ok {
+				result = append(result, res)
+			}
 		}
 	}
-	iterator.count = r.count
-	return iterator.next
+	return result
 }
 
 // All returns all occurrences of the RRule.
@@ -754,13 +1365,74 @@ func (r *RRule) All() []time.Time {
 	return all(r.Iterator())
 }
 
+// AllWithContext is like All, but stops when ctx is done and returns the occurrences
+// found so far with the context error, ex. to bound the expansion of an unbounded rule.
+func (r *RRule) AllWithContext(ctx context.Context) ([]time.Time, error) {
+	return allWithContext(ctx, r.iteratorWithContext(ctx))
+}
+
+// AllUnix returns all occurrences of the RRule as Unix times, the seconds elapsed
+// since January 1, 1970 UTC, without building a slice of time.Time.
+// Unix times don't depend on the time zone, they suit batch processing
+// of many occurrences when no time.Time is needed.
+func (r *RRule) AllUnix() []int64 {
+	result := []int64{}
+	next := r.Iterator()
+	for {
+		dt, ok := next()
+		if !ok {
+			return result
+		}
+		result = append(result, dt.Unix())
+	}
+}
+
 // Between returns all the occurrences of the RRule between after and before.
 // The inc keyword defines what happens if after and/or before are themselves occurrences.
 // With inc == True, they will be included in the list, if they are found in the recurrence set.
+// DTSTART is no special case: if after is DTSTART and DTSTART is an occurrence, it's included
+// only with inc == True. To exclude the start of the series but include before,
+// call BetweenBounds(r.DTStart(), before, false, true).
 func (r *RRule) Between(after, before time.Time, inc bool) []time.Time {
 	return between(r.Iterator(), after, before, inc)
 }
 
+// BetweenBounds is like Between but the inclusivity of after and before is set independently,
+// ex. incStart == true and incEnd == false gives the half-open window [after, before).
+func (r *RRule) BetweenBounds(after, before time.Time, incStart, incEnd bool) []time.Time {
+	return betweenBounds(r.Iterator(), after, before, incStart, incEnd)
+}
+
+// defaultBetweenLimit is the number of occurrences BetweenLimited returns at most if max <= 0.
+const defaultBetweenLimit = 100000
+
+// BetweenLimited is like Between but returns at most max occurrences, and whether there were
+// more of them in the window, so that a large window on a dense rule, ex. SECONDLY over a year,
+// doesn't build an enormous slice. If max <= 0, a limit of 100000 occurrences applies.
+func (r *RRule) BetweenLimited(after, before time.Time, inc bool, max int) ([]time.Time, bool) {
+	if max <= 0 {
+		max = defaultBetweenLimit
+	}
+	return betweenLimited(r.Iterator(), after, before, inc, max)
+}
+
+// CountBetween returns the number of occurrences of the RRule between after and before,
+// with the same inc semantics as Between, without building them into a slice.
+// Iteration stops once past before.
+func (r *RRule) CountBetween(after, before time.Time, inc bool) int {
+	if before.Before(r.dtstart) || !r.until.IsZero() && after.After(r.until) {
+		return 0
+	}
+	return countBetween(r.Iterator(), after, before, inc)
+}
+
+// ForEach calls fn for each occurrence of the RRule between after and before, both included,
+// in order, without building them into a slice. Iteration stops once past before,
+// or as soon as fn returns false.
+func (r *RRule) ForEach(after, before time.Time, fn func(time.Time) bool) {
+	forEach(r.Iterator(), after, before, fn)
+}
+
 // Before returns the last recurrence before the given datetime instance,
 // or time.Time's zero value if no recurrence match.
 // The inc keyword defines what happens if dt is an occurrence.
@@ -776,3 +1448,429 @@ func (r *RRule) Before(dt time.Time, inc bool) time.Time {
 func (r *RRule) After(dt time.Time, inc bool) time.Time {
 	return after(r.Iterator(), dt, inc)
 }
+
+// AllInLocation returns all occurrences of the RRule converted to loc.
+// Occurrences are computed in the DTSTART zone, so BYHOUR=9 stays 9am there across DST,
+// and only then converted for output.
+func (r *RRule) AllInLocation(loc *time.Location) []time.Time {
+	return inLocation(r.All(), loc)
+}
+
+// BetweenInLocation is like Between, with the occurrences converted to loc, see AllInLocation.
+func (r *RRule) BetweenInLocation(after, before time.Time, inc bool, loc *time.Location) []time.Time {
+	return inLocation(r.Between(after, before, inc), loc)
+}
+
+// BeforeInLocation is like Before, with the occurrence converted to loc, see AllInLocation.
+func (r *RRule) BeforeInLocation(dt time.Time, inc bool, loc *time.Location) time.Time {
+	if result := r.Before(dt, inc); !result.IsZero() {
+		return result.In(loc)
+	}
+	return time.Time{}
+}
+
+// AfterInLocation is like After, with the occurrence converted to loc, see AllInLocation.
+func (r *RRule) AfterInLocation(dt time.Time, inc bool, loc *time.Location) time.Time {
+	if result := r.After(dt, inc); !result.IsZero() {
+		return result.In(loc)
+	}
+	return time.Time{}
+}
+
+// LastN returns the last n occurrences of a bounded RRule, in chronological order.
+// Only the last n occurrences are kept in memory while iterating.
+// It returns an error if the RRule has neither COUNT nor UNTIL.
+func (r *RRule) LastN(n int) ([]time.Time, error) {
+	if r.count == 0 && r.until.IsZero() {
+		return nil, errUnbounded
+	}
+	return lastN(r.Iterator(), n), nil
+}
+
+// ReverseIterator returns an iterator walking the occurrences of a bounded RRule
+// from the last one to the first.
+// It returns an error if the RRule has neither COUNT nor UNTIL.
+func (r *RRule) ReverseIterator() (Next, error) {
+	if r.count == 0 && r.until.IsZero() {
+		return nil, errUnbounded
+	}
+	return reverseTimeSliceIterator(r.All()), nil
+}
+
+// Count returns the number of occurrences of a bounded RRule without building them into a slice.
+// COUNT is only an upper bound, as a rule may have fewer occurrences (ex. BYMONTHDAY=31 with BYMONTH=2),
+// so occurrences are always iterated.
+// It returns an error if the RRule has neither COUNT nor UNTIL.
+func (r *RRule) Count() (int, error) {
+	if r.count == 0 && r.until.IsZero() {
+		return 0, errUnbounded
+	}
+	next := r.Iterator()
+	total := 0
+	for _, ok := next(); ok; _, ok = next() {
+		total++
+	}
+	return total, nil
+}
+
+// Terminates reports whether the RRule has a finite number of occurrences,
+// that is whether it has COUNT or UNTIL, and if so returns its last occurrence,
+// or time.Time's zero value if it has none.
+// When both COUNT and UNTIL are set, the one reached first ends the rule.
+func (r *RRule) Terminates() (bool, time.Time) {
+	if r.count == 0 && r.until.IsZero() {
+		return false, time.Time{}
+	}
+	last := lastN(r.Iterator(), 1)
+	if len(last) == 0 {
+		return true, time.Time{}
+	}
+	return true, last[0]
+}
+
+// IsInfinite reports whether the RRule has neither COUNT nor UNTIL, so that All would not return.
+// Use Between, NextN or AllWithLimit on such a rule.
+func (r *RRule) IsInfinite() bool {
+	return r.count == 0 && r.until.IsZero()
+}
+
+// Step returns the constant interval between consecutive occurrences of the RRule,
+// ex. 14 days for FREQ=WEEKLY;INTERVAL=2, and false if it's not constant:
+// for YEARLY and MONTHLY rules, rules with BYSETPOS or BY* parts selecting days other than
+// a single weekday of a WEEKLY rule, rules with several occurrences per period,
+// and rules with ROption.IncludeDTStart whose DTSTART doesn't match them.
+// The interval is nominal, a day lasts 24 hours: across a DST change in the DTSTART zone,
+// daily and weekly occurrences at the same local time are apart by the interval plus or minus the shift.
+func (r *RRule) Step() (time.Duration, bool) {
+	if len(r.bysetpos) != 0 || len(r.bymonth) != 0 || len(r.byweekno) != 0 || len(r.byyearday) != 0 ||
+		len(r.bymonthday) != 0 || len(r.bynmonthday) != 0 || len(r.bynweekday) != 0 || len(r.byeaster) != 0 {
+		return 0, false
+	}
+	// one checks the parts giving the single occurrence of a period,
+	// none the ones which would filter out some periods
+	one := func(values ...int) bool {
+		for _, n := range values {
+			if n != 1 {
+				return false
+			}
+		}
+		return true
+	}
+	none := func(values ...int) bool {
+		for _, n := range values {
+			if n != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	var unit time.Duration
+	var regular bool
+	switch r.freq {
+	case WEEKLY:
+		unit, regular = 7*24*time.Hour, one(len(r.byweekday), len(r.byhour), len(r.byminute), len(r.bysecond))
+	case DAILY:
+		unit, regular = 24*time.Hour, one(len(r.byhour), len(r.byminute), len(r.bysecond)) && none(len(r.byweekday))
+	case HOURLY:
+		unit, regular = time.Hour, one(len(r.byminute), len(r.bysecond)) && none(len(r.byweekday), len(r.byhour))
+	case MINUTELY:
+		unit, regular = time.Minute, one(len(r.bysecond)) && none(len(r.byweekday), len(r.byhour), len(r.byminute))
+	case SECONDLY:
+		unit, regular = time.Second, none(len(r.byweekday), len(r.byhour), len(r.byminute), len(r.bysecond))
+	}
+	if !regular {
+		return 0, false
+	}
+	if r.includeDTStart {
+		// a DTSTART which doesn't match the rule comes before the regular occurrences
+		if first, ok := r.newIterator().next(); !ok || !first.Equal(r.dtstart) {
+			return 0, false
+		}
+	}
+	return time.Duration(r.interval) * unit, true
+}
+
+// Occurrence is an occurrence of an event with a duration.
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// addDuration adds d to t as RFC 5545 does for DURATION values:
+// whole days of d are added as calendar days in t's location, keeping the wall clock
+// time across DST transitions, and the remainder is added as exact elapsed time.
+func addDuration(t time.Time, d time.Duration) time.Time {
+	days := d / (24 * time.Hour)
+	return t.AddDate(0, 0, int(days)).Add(d - days*24*time.Hour)
+}
+
+// AllWithDuration returns all occurrences of the RRule with their end, for an event lasting d.
+// The end is computed in the DTSTART location: whole days of d are calendar days,
+// so a 24 hour event ends at the same wall clock time on the next day even across
+// a DST transition, while the remainder is exact elapsed time,
+// so a 1 hour event starting at 01:30 on a spring forward day ends at 03:30.
+func (r *RRule) AllWithDuration(d time.Duration) []Occurrence {
+	result := []Occurrence{}
+	next := r.Iterator()
+	for dt, ok := next(); ok; dt, ok = next() {
+		result = append(result, Occurrence{dt, addDuration(dt, d)})
+	}
+	return result
+}
+
+// Equals reports whether r and other were built from the same options,
+// regardless of the order of the BY* lists and of the default INTERVAL being explicit.
+func (r *RRule) Equals(other *RRule) bool {
+	a, b := r.OrigOptions, other.OrigOptions
+	a.Normalize()
+	b.Normalize()
+	return a.equals(&b)
+}
+
+// clone returns a copy of the rule with its own OrigOptions,
+// the internal slices are never modified after NewRRule so they are shared.
+func (r *RRule) clone() *RRule {
+	result := *r
+	result.OrigOptions = r.OrigOptions.Clone()
+	return &result
+}
+
+// withDTStart returns a copy of the RRule with the given DTSTART.
+func (r *RRule) withDTStart(dtstart time.Time) *RRule {
+	option := r.OrigOptions.Clone()
+	option.Dtstart = dtstart
+	// the option was already accepted by NewRRule
+	result, _ := NewRRule(option)
+	return result
+}
+
+// shift returns a copy of the RRule with DTSTART and UNTIL moved by d.
+func (r *RRule) shift(d time.Duration) *RRule {
+	option := r.OrigOptions.Clone()
+	option.Dtstart = r.dtstart.Add(d)
+	if !option.Until.IsZero() {
+		option.Until = option.Until.Add(d)
+	}
+	// the option was already accepted by NewRRule
+	result, _ := NewRRule(option)
+	return result
+}
+
+// First returns the first occurrence of the RRule, which is DTSTART
+// unless the BY* parts push the first valid occurrence later,
+// and false if the rule has no occurrence.
+func (r *RRule) First() (time.Time, bool) {
+	return r.Iterator()()
+}
+
+// Last returns the last occurrence of a rule bounded by COUNT or UNTIL,
+// and false if the rule is unbounded or has no occurrence.
+// Rules bounded by UNTIL only skip ahead to the period before UNTIL
+// instead of iterating from DTSTART, which keeps the DTSTART phase.
+func (r *RRule) Last() (time.Time, bool) {
+	if r.count == 0 && r.until.IsZero() {
+		return time.Time{}, false
+	}
+	if r.count == 0 {
+		if last := lastN(r.skipTo(r.until).Iterator(), 1); len(last) != 0 {
+			return last[0], true
+		}
+	}
+	if last := lastN(r.Iterator(), 1); len(last) != 0 {
+		return last[0], true
+	}
+	return time.Time{}, false
+}
+
+// skipTo returns a copy of the RRule starting a whole number of periods after DTSTART,
+// at least one period before t, so that iterating it reaches t without walking the
+// periods in between. MONTHLY and YEARLY copies start on the first day of their period.
+// COUNT is not adjusted, callers skip only rules without it.
+func (r *RRule) skipTo(t time.Time) *RRule {
+	periods := (r.periodsBetween(r.dtstart, t.In(r.dtstart.Location()))/r.interval - 1) * r.interval
+	if periods <= 0 {
+		return r
+	}
+	year, month, day := r.dtstart.Date()
+	hour, minute, second := r.dtstart.Clock()
+	switch r.freq {
+	case YEARLY:
+		year, month, day = year+periods, time.January, 1
+	case MONTHLY:
+		month, day = month+time.Month(periods), 1
+	case WEEKLY:
+		day += periods * 7
+	case DAILY:
+		day += periods
+	case HOURLY:
+		hour += periods
+	case MINUTELY:
+		minute += periods
+	case SECONDLY:
+		second += periods
+	}
+	dtstart := time.Date(year, month, day, hour, minute, second, 0, r.dtstart.Location())
+	// the iterator counts periods on the wall clock, which a DST gap would shift
+	if wall := time.Date(year, month, day, hour, minute, second, 0, time.UTC); r.freq >= HOURLY &&
+		(dtstart.Hour() != wall.Hour() || dtstart.Minute() != wall.Minute()) {
+		return r
+	}
+	result := *r
+	result.dtstart = dtstart
+	// the shifted DTSTART is not an occurrence
+	result.includeDTStart = false
+	return &result
+}
+
+// Next returns the first occurrence strictly after from,
+// and false if there is none.
+func (r *RRule) Next(from time.Time) (time.Time, bool) {
+	dt := r.After(from, false)
+	return dt, !dt.IsZero()
+}
+
+// Nth returns the occurrence at the 0-based index i, iterating no further than it,
+// and false if the RRule has fewer occurrences.
+func (r *RRule) Nth(i int) (time.Time, bool) {
+	if i < 0 {
+		return time.Time{}, false
+	}
+	next := r.Iterator()
+	for {
+		dt, ok := next()
+		if !ok || i == 0 {
+			return dt, ok
+		}
+		i--
+	}
+}
+
+// IndexOf returns the 0-based index of t if it's an occurrence of the RRule,
+// iterating no further than t.
+func (r *RRule) IndexOf(t time.Time) (int, bool) {
+	next := r.Iterator()
+	for i := 0; ; i++ {
+		dt, ok := next()
+		if !ok || dt.After(t) {
+			return 0, false
+		}
+		if dt.Equal(t) {
+			return i, true
+		}
+	}
+}
+
+// OnDate returns the occurrences of the RRule falling on the calendar date of d
+// in loc, that is from midnight to the next midnight in loc.
+// Iteration stops at the end of that day.
+func (r *RRule) OnDate(d time.Time, loc *time.Location) []time.Time {
+	year, month, day := d.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	end := time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+	return r.BetweenBounds(start, end, true, false)
+}
+
+// Dates returns the distinct calendar dates in loc of the occurrences between after
+// and before included, as midnights in loc, in order.
+// Several occurrences on a day, as for an HOURLY rule, give a single date.
+func (r *RRule) Dates(after, before time.Time, loc *time.Location) []time.Time {
+	result := []time.Time{}
+	for _, dt := range r.Between(after, before, true) {
+		year, month, day := dt.In(loc).Date()
+		date := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		if len(result) == 0 || !result[len(result)-1].Equal(date) {
+			result = append(result, date)
+		}
+	}
+	return result
+}
+
+// NextN returns up to n occurrences strictly after from, fewer if COUNT or UNTIL ends the RRule before.
+// It doesn't need an upper bound, unlike Between, so it works for unbounded rules.
+func (r *RRule) NextN(from time.Time, n int) []time.Time {
+	result := []time.Time{}
+	next := r.Iterator()
+	for len(result) < n {
+		dt, ok := next()
+		if !ok {
+			break
+		}
+		if dt.After(from) {
+			result = append(result, dt)
+		}
+	}
+	return result
+}
+
+// AllAfter returns at most limit occurrences after anchor, or at anchor if inc is true.
+// Unlike a copy of the RRule with DTSTART moved to anchor, the occurrences keep
+// the phase given by DTSTART, which suits paging from the last seen occurrence.
+func (r *RRule) AllAfter(anchor time.Time, inc bool, limit int) []time.Time {
+	result := []time.Time{}
+	next := r.Iterator()
+	for len(result) < limit {
+		dt, ok := next()
+		if !ok {
+			break
+		}
+		if inc && !dt.Before(anchor) || !inc && dt.After(anchor) {
+			result = append(result, dt)
+		}
+	}
+	return result
+}
+
+// Contains reports whether t, truncated to the second, is an occurrence of the RRule,
+// respecting COUNT and UNTIL. Times outside of DTSTART and UNTIL are rejected
+// without iterating. Without COUNT the iteration starts a period before the one
+// of t, otherwise the occurrences are iterated from DTSTART no further than t.
+func (r *RRule) Contains(t time.Time) bool {
+	t = t.Truncate(time.Second)
+	if t.Before(r.dtstart) || !r.until.IsZero() && t.After(r.until) {
+		return false
+	}
+	start := r
+	if r.count == 0 {
+		start = r.skipTo(t)
+	}
+	dt := start.After(t, true)
+	return !dt.IsZero() && dt.Equal(t)
+}
+
+// AllWithLimit returns at most max occurrences of the RRule, and whether it has more.
+// It's a safe way to expand rules from untrusted sources, including unbounded ones.
+func (r *RRule) AllWithLimit(max int) ([]time.Time, bool) {
+	result := []time.Time{}
+	next := r.Iterator()
+	for {
+		dt, ok := next()
+		if !ok {
+			return result, false
+		}
+		if len(result) >= max {
+			return result, true
+		}
+		result = append(result, dt)
+	}
+}
+
+// AllUntil returns the occurrences of the RRule up to cap included, stopping earlier
+// if COUNT or UNTIL end the rule first. Unlike a copy with UNTIL set to cap,
+// COUNT still applies, counted from DTSTART.
+func (r *RRule) AllUntil(cap time.Time) []time.Time {
+	result := []time.Time{}
+	next := r.Iterator()
+	for {
+		dt, ok := next()
+		if !ok || dt.After(cap) {
+			return result
+		}
+		result = append(result, dt)
+	}
+}
+
+// DTStart returns the DTSTART of the RRule as used for the expansion,
+// truncated to the second, or the construction time if the option had none.
+func (r *RRule) DTStart() time.Time {
+	return r.dtstart
+}
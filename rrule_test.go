@@ -1,6 +1,8 @@
 package rrule
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 )
@@ -24,6 +26,15 @@ func TestNoDtstart(t *testing.T) {
 	}
 }
 
+func TestNoDtstartNow(t *testing.T) {
+	r, _ := NewRRuleFrom(ROption{Freq: MONTHLY, Count: 2}, time.Date(1997, 9, 2, 9, 0, 0, 500, time.UTC))
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 10, 2, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
 func TestBadBySetPos(t *testing.T) {
 	_, e := NewRRule(ROption{Freq: MONTHLY, Count: 1, Bysetpos: []int{0},
 		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
@@ -445,6 +456,51 @@ func TestYearlyByEaster(t *testing.T) {
 	}
 }
 
+func TestYearlyByEasterSunday(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Count:    5,
+		Byeaster: []int{0},
+		Dtstart:  time.Date(2019, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2019, 4, 21, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 4, 12, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 4, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 4, 17, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 4, 9, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestYearlyByEasterGoodFriday(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Count:    5,
+		Byeaster: []int{-2},
+		Dtstart:  time.Date(2019, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2019, 4, 19, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 4, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 4, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 4, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 4, 7, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestYearlyByEasterOutOfYear(t *testing.T) {
+	// Easter is never 300 days after January 1st, nor 300 days before December 31st
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Count:    1,
+		Byeaster: []int{-300, 300},
+		Dtstart:  time.Date(2019, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
 func TestYearlyByEasterPos(t *testing.T) {
 	r, _ := NewRRule(ROption{Freq: YEARLY,
 		Count:    3,
@@ -719,6 +775,25 @@ func TestMonthlyByNWeekDay(t *testing.T) {
 	}
 }
 
+func TestMonthlyByFirstAndLastWeekDay(t *testing.T) {
+	// January 2021 has 31 days and 4 Mondays, February 2021 has 28 days and 4 Mondays,
+	// March 2021 has 5 Mondays
+	r, _ := NewRRule(ROption{Freq: MONTHLY,
+		Count:     6,
+		Byweekday: []Weekday{MO.Nth(-1), MO.Nth(1)},
+		Dtstart:   time.Date(2021, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2021, 1, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 25, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 2, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 2, 22, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 3, 29, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
 func TestMonthlyByNWeekDayLarge(t *testing.T) {
 	r, _ := NewRRule(ROption{Freq: MONTHLY,
 		Count:     3,
@@ -778,6 +853,25 @@ func TestMonthlyByMonthAndNWeekDayLarge(t *testing.T) {
 	}
 }
 
+func TestMonthlyByLastMonthDay(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: MONTHLY,
+		Count:      14,
+		Bymonthday: []int{-1},
+		Dtstart:    time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{}
+	for _, day := range []struct {
+		year, month, day int
+	}{{2023, 1, 31}, {2023, 2, 28}, {2023, 3, 31}, {2023, 4, 30}, {2023, 5, 31}, {2023, 6, 30},
+		{2023, 7, 31}, {2023, 8, 31}, {2023, 9, 30}, {2023, 10, 31}, {2023, 11, 30}, {2023, 12, 31},
+		{2024, 1, 31}, {2024, 2, 29}} {
+		want = append(want, time.Date(day.year, time.Month(day.month), day.day, 9, 0, 0, 0, time.UTC))
+	}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
 func TestMonthlyByMonthDayAndWeekDay(t *testing.T) {
 	r, _ := NewRRule(ROption{Freq: MONTHLY,
 		Count:      3,
@@ -3568,6 +3662,117 @@ func TestWkStIntervalSU(t *testing.T) {
 	}
 }
 
+func TestWkStIntervalSUFullYear(t *testing.T) {
+	// FREQ=WEEKLY;INTERVAL=2 from Wednesday 2022-01-05 over a year: the weeks start on WKST,
+	// the week of DTSTART is the first one, and every other week is kept
+	dtstart := time.Date(2022, 1, 5, 9, 0, 0, 0, time.UTC)
+	until := time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC)
+	expected := func(firstWeek time.Time, wdays ...time.Weekday) []time.Time {
+		result := []time.Time{}
+		for day := dtstart; !day.After(until); day = day.AddDate(0, 0, 1) {
+			week := int(day.Sub(firstWeek).Hours()) / 24 / 7
+			for _, wday := range wdays {
+				if week%2 == 0 && day.Weekday() == wday {
+					result = append(result, day)
+				}
+			}
+		}
+		return result
+	}
+	cases := []struct {
+		wkst      Weekday
+		byweekday []Weekday
+		want      []time.Time
+	}{
+		{SU, []Weekday{MO, WE, FR}, expected(time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC),
+			time.Monday, time.Wednesday, time.Friday)},
+		{SU, []Weekday{MO, WE, FR, SU}, expected(time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC),
+			time.Monday, time.Wednesday, time.Friday, time.Sunday)},
+		{MO, []Weekday{MO, WE, FR, SU}, expected(time.Date(2022, 1, 3, 9, 0, 0, 0, time.UTC),
+			time.Monday, time.Wednesday, time.Friday, time.Sunday)},
+	}
+	for _, c := range cases {
+		r, _ := NewRRule(ROption{Freq: WEEKLY,
+			Interval:  2,
+			Byweekday: c.byweekday,
+			Wkst:      c.wkst,
+			Dtstart:   dtstart,
+			Until:     until})
+		value := r.All()
+		if !timesEqual(value, c.want) {
+			t.Errorf("WKST=%v BYDAY=%v: get %v, want %v", c.wkst, c.byweekday, value, c.want)
+		}
+	}
+}
+
+func TestByWeekNo1WkstMO(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Count:     4,
+		Byweekno:  []int{1},
+		Byweekday: []Weekday{SU},
+		Wkst:      MO,
+		Dtstart:   time.Date(2018, 12, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2019, 1, 6, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 9, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestByWeekNo1WkstSU(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Count:     4,
+		Byweekno:  []int{1},
+		Byweekday: []Weekday{SU},
+		Wkst:      SU,
+		Dtstart:   time.Date(2018, 12, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2018, 12, 30, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 12, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestByWeekNo1AcrossYearWkstMO(t *testing.T) {
+	// 2020-01-01 is a Wednesday, week 1 is Monday 2019-12-30 to Sunday 2020-01-05
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Byweekno: []int{1},
+		Wkst:     MO,
+		Dtstart:  time.Date(2019, 12, 1, 9, 0, 0, 0, time.UTC),
+		Until:    time.Date(2020, 2, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{}
+	for i := 0; i < 7; i++ {
+		want = append(want, time.Date(2019, 12, 30+i, 9, 0, 0, 0, time.UTC))
+	}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestByWeekNo1AcrossYearWkstSU(t *testing.T) {
+	// with WKST=SU, week 1 is Sunday 2019-12-29 to Saturday 2020-01-04
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Byweekno: []int{1},
+		Wkst:     SU,
+		Dtstart:  time.Date(2019, 12, 1, 9, 0, 0, 0, time.UTC),
+		Until:    time.Date(2020, 2, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{}
+	for i := 0; i < 7; i++ {
+		want = append(want, time.Date(2019, 12, 29+i, 9, 0, 0, 0, time.UTC))
+	}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
 func TestDTStartIsDate(t *testing.T) {
 	r, _ := NewRRule(ROption{Freq: DAILY,
 		Count:   3,
@@ -3679,3 +3884,1609 @@ func TestBetweenInc(t *testing.T) {
 		t.Errorf("get %v, want %v", value, want)
 	}
 }
+
+func TestIteratorUnbounded(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: SECONDLY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	next := r.Iterator()
+	value := []time.Time{}
+	for i := 0; i < 3; i++ {
+		v, ok := next()
+		if !ok {
+			t.Fatalf("iterator exhausted after %d occurrences", i)
+		}
+		value = append(value, v)
+	}
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 2, 9, 0, 1, 0, time.UTC),
+		time.Date(1997, 9, 2, 9, 0, 2, 0, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestIteratorExhausted(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		Until:   time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)})
+	next := r.Iterator()
+	next()
+	next()
+	for i := 0; i < 3; i++ {
+		if v, ok := next(); ok {
+			t.Errorf("get %v, want exhausted iterator", v)
+		}
+	}
+}
+
+func TestLastNCount(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   10,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 11, 9, 0, 0, 0, time.UTC)}
+	value, err := r.LastN(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestLastNUntil(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: WEEKLY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		Until:   time.Date(1997, 12, 31, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 12, 23, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 12, 30, 9, 0, 0, 0, time.UTC)}
+	value, err := r.LastN(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestLastNMoreThanCount(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   2,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	value, _ := r.LastN(5)
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestLastNUnbounded(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if _, err := r.LastN(3); err == nil {
+		t.Error("get nil, want error")
+	}
+	if _, err := r.ReverseIterator(); err == nil {
+		t.Error("get nil, want error")
+	}
+}
+
+func TestReverseIterator(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   3,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	next, err := r.ReverseIterator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)}
+	value := all(next)
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	option := ROption{Freq: MONTHLY,
+		Bysetpos:   []int{-1},
+		Bymonth:    []int{1, 12},
+		Bymonthday: []int{-31, 31},
+		Byyearday:  []int{-366, 366},
+		Byweekno:   []int{-53, 53},
+		Byhour:     []int{0, 23},
+		Byminute:   []int{0, 59},
+		Bysecond:   []int{0, 59},
+		Byeaster:   []int{-366, 0, 366}}
+	if err := option.Validate(); err != nil {
+		t.Errorf("get %v, want nil", err)
+	}
+	option = ROption{Freq: MONTHLY, Byweekday: []Weekday{MO.Nth(-5), TU.Nth(5), WE}}
+	if err := option.Validate(); err != nil {
+		t.Errorf("get %v, want nil", err)
+	}
+	option = ROption{Freq: YEARLY, Byweekday: []Weekday{MO.Nth(-53), TU.Nth(53)}}
+	if err := option.Validate(); err != nil {
+		t.Errorf("get %v, want nil", err)
+	}
+}
+
+func TestStrToRRuleInvalidOrdinal(t *testing.T) {
+	for _, s := range []string{
+		"FREQ=MONTHLY;BYDAY=-6MO;COUNT=1",
+		"FREQ=YEARLY;BYDAY=+60MO;COUNT=1",
+		"FREQ=MONTHLY;BYDAY=0MO;COUNT=1",
+		"FREQ=MONTHLY;BYDAY=+0MO;COUNT=1",
+	} {
+		if _, err := StrToRRule(s); err == nil {
+			t.Errorf("StrToRRule(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestValidateInvalid(t *testing.T) {
+	cases := []struct {
+		option ROption
+		want   string
+	}{
+		{ROption{Bymonth: []int{13}}, "BYMONTH value 13"},
+		{ROption{Bymonth: []int{0}}, "BYMONTH value 0"},
+		{ROption{Bymonthday: []int{0}}, "BYMONTHDAY value 0"},
+		{ROption{Bymonthday: []int{-32}}, "BYMONTHDAY value -32"},
+		{ROption{Byhour: []int{99}}, "BYHOUR value 99"},
+		{ROption{Byminute: []int{60}}, "BYMINUTE value 60"},
+		{ROption{Bysecond: []int{-1}}, "BYSECOND value -1"},
+		{ROption{Byyearday: []int{367}}, "BYYEARDAY value 367"},
+		{ROption{Byweekno: []int{-54}}, "BYWEEKNO value -54"},
+		{ROption{Bysetpos: []int{0}}, "BYSETPOS value 0"},
+		{ROption{Byeaster: []int{-367}}, "BYEASTER value -367"},
+		{ROption{Freq: MONTHLY, Bysetpos: []int{1}}, "BYSETPOS"},
+		{ROption{Bymonth: []int{1, 13}, Byhour: []int{99}}, "BYMONTH value 13"},
+		{ROption{Count: 3, Until: time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)}, "COUNT and UNTIL"},
+		{ROption{Freq: MONTHLY, Byweekday: []Weekday{MO.Nth(-6)}}, "BYDAY value -6MO"},
+		{ROption{Freq: MONTHLY, Byweekday: []Weekday{MO, TU.Nth(6)}}, "BYDAY value +6TU"},
+		{ROption{Freq: YEARLY, Byweekday: []Weekday{MO.Nth(54)}}, "BYDAY value +54MO"},
+		{ROption{Freq: YEARLY, Byweekday: []Weekday{FR.Nth(-60)}}, "BYDAY value -60FR"},
+	}
+	for _, c := range cases {
+		err := c.option.Validate()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("Validate(%v) = %v, want error containing %q", c.option.String(), err, c.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   10,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if value, err := r.Count(); err != nil || value != 10 {
+		t.Errorf("get %v, %v, want 10", value, err)
+	}
+	r, _ = NewRRule(ROption{Freq: WEEKLY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		Until:   time.Date(1997, 12, 31, 9, 0, 0, 0, time.UTC)})
+	if value, err := r.Count(); err != nil || value != 18 {
+		t.Errorf("get %v, %v, want 18", value, err)
+	}
+	r, _ = NewRRule(ROption{Freq: YEARLY,
+		Count:      3,
+		Bymonth:    []int{2},
+		Bymonthday: []int{31},
+		Dtstart:    time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if value, err := r.Count(); err != nil || value != 0 {
+		t.Errorf("get %v, %v, want 0", value, err)
+	}
+}
+
+func TestCountUnbounded(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if _, err := r.Count(); err == nil {
+		t.Error("get nil, want error")
+	}
+}
+
+func TestAllWithDuration(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   2,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []Occurrence{
+		{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC), time.Date(1997, 9, 2, 10, 30, 0, 0, time.UTC)},
+		{time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC), time.Date(1997, 9, 3, 10, 30, 0, 0, time.UTC)},
+	}
+	value := r.AllWithDuration(90 * time.Minute)
+	if len(value) != len(want) || value[0] != want[0] || value[1] != want[1] {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestAllWithDurationDST(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	// DST starts on 2022-03-13 at 02:00 in New York
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   2,
+		Dtstart: time.Date(2022, 3, 12, 1, 30, 0, 0, newYork)})
+
+	value := r.AllWithDuration(time.Hour)
+	want := time.Date(2022, 3, 13, 3, 30, 0, 0, newYork)
+	if end := value[1].End; !end.Equal(want) || end.Sub(value[1].Start) != time.Hour {
+		t.Errorf("get %v, want %v", end, want)
+	}
+
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Count:   1,
+		Dtstart: time.Date(2022, 3, 12, 9, 0, 0, 0, newYork)})
+	value = r.AllWithDuration(24 * time.Hour)
+	want = time.Date(2022, 3, 13, 9, 0, 0, 0, newYork)
+	if end := value[0].End; !end.Equal(want) || end.Sub(value[0].Start) != 23*time.Hour {
+		t.Errorf("get %v, want %v", end, want)
+	}
+}
+
+func TestSkipOmit(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: MONTHLY,
+		Count:      3,
+		Bymonthday: []int{31},
+		Dtstart:    time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 5, 31, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSkipBackward(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: MONTHLY,
+		Count:      5,
+		Bymonthday: []int{31},
+		Skip:       SkipBackward,
+		Dtstart:    time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 30, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 31, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSkipForward(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: MONTHLY,
+		Count:      5,
+		Bymonthday: []int{1, 30},
+		Skip:       SkipForward,
+		Dtstart:    time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 30, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 2, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 3, 30, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSkipYearlyLeapDay(t *testing.T) {
+	option := ROption{Freq: YEARLY,
+		Count:      3,
+		Bymonth:    []int{2},
+		Bymonthday: []int{29},
+		Dtstart:    time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)}
+	option.Skip = SkipBackward
+	r, _ := NewRRule(option)
+	want := []time.Time{time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	option.Skip = SkipForward
+	r, _ = NewRRule(option)
+	want = []time.Time{time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	value = r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSkipNegativeMonthDay(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: MONTHLY,
+		Count:      3,
+		Bymonthday: []int{-30},
+		Skip:       SkipForward,
+		Dtstart:    time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 2, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 3, 2, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSubSecondDtstart(t *testing.T) {
+	option := ROption{Freq: DAILY, Count: 2,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 700000000, time.UTC)}
+	r, _ := NewRRule(option)
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	str := "FREQ=DAILY;DTSTART=19970902T090000Z;COUNT=2"
+	if value := r.String(); value != str {
+		t.Errorf("get %q, want %q", value, str)
+	}
+	option.RoundDTStart()
+	r, _ = NewRRule(option)
+	want = []time.Time{time.Date(1997, 9, 2, 9, 0, 1, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 1, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	bymonth := []int{3, 1, 3}
+	option := ROption{Freq: MONTHLY,
+		Interval:  1,
+		Bymonth:   bymonth,
+		Byweekday: []Weekday{FR, MO, FR.Nth(-1), MO.Nth(1)},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 500000000, time.UTC)}
+	option.Normalize()
+	want := "FREQ=MONTHLY;DTSTART=19970902T090000Z;BYMONTH=1,3;BYDAY=-1FR,MO,FR,+1MO"
+	if value := option.String(); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if option.Dtstart.Nanosecond() != 0 {
+		t.Errorf("get %v, want DTSTART truncated to seconds", option.Dtstart)
+	}
+	if bymonth[0] != 3 {
+		t.Errorf("get %v, want original slice untouched", bymonth)
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	// 1997-09-02 is a Tuesday
+	cases := []struct {
+		str  string
+		want string
+	}{
+		{"FREQ=WEEKLY;DTSTART=19970902T090000Z;INTERVAL=1;WKST=MO;COUNT=5;BYDAY=TU",
+			"FREQ=WEEKLY;DTSTART=19970902T090000Z;COUNT=5"},
+		{"FREQ=WEEKLY;DTSTART=19970902T090000Z;COUNT=5;BYDAY=TU;BYHOUR=9;BYMINUTE=0;BYSECOND=0",
+			"FREQ=WEEKLY;DTSTART=19970902T090000Z;COUNT=5"},
+		{"FREQ=MONTHLY;DTSTART=19970902T090000Z;WKST=SU;COUNT=5;BYMONTHDAY=2",
+			"FREQ=MONTHLY;DTSTART=19970902T090000Z;COUNT=5"},
+		{"FREQ=YEARLY;DTSTART=19970902T090000Z;COUNT=3;BYMONTH=9;BYMONTHDAY=2",
+			"FREQ=YEARLY;DTSTART=19970902T090000Z;COUNT=3"},
+		// not redundant
+		{"FREQ=WEEKLY;DTSTART=19970902T090000Z;INTERVAL=2;WKST=SU;COUNT=5;BYDAY=TU,TH",
+			"FREQ=WEEKLY;DTSTART=19970902T090000Z;INTERVAL=2;WKST=SU;COUNT=5;BYDAY=TU,TH"},
+		{"FREQ=WEEKLY;DTSTART=19970902T090000Z;COUNT=5;BYDAY=MO",
+			"FREQ=WEEKLY;DTSTART=19970902T090000Z;COUNT=5;BYDAY=MO"},
+		{"FREQ=YEARLY;DTSTART=19970902T090000Z;COUNT=3;BYMONTHDAY=2",
+			"FREQ=YEARLY;DTSTART=19970902T090000Z;COUNT=3;BYMONTHDAY=2"},
+		{"FREQ=HOURLY;DTSTART=19970902T090000Z;COUNT=3;BYHOUR=9",
+			"FREQ=HOURLY;DTSTART=19970902T090000Z;COUNT=3;BYHOUR=9"},
+		{"FREQ=MONTHLY;DTSTART=19970902T090000Z;COUNT=3;BYMONTHDAY=2;BYDAY=TU",
+			"FREQ=MONTHLY;DTSTART=19970902T090000Z;COUNT=3;BYMONTHDAY=2;BYDAY=TU"},
+	}
+	for _, c := range cases {
+		option, err := StrToROption(c.str)
+		if err != nil {
+			t.Fatalf("StrToROption(%q) returned error: %v", c.str, err)
+		}
+		minimized := option.Minimize()
+		if value := minimized.String(); value != c.want {
+			t.Errorf("get %v, want %v", value, c.want)
+		}
+		r, _ := NewRRule(*option)
+		m, _ := NewRRule(minimized)
+		if value, want := m.All(), r.All(); !timesEqual(value, want) {
+			t.Errorf("%s: get %v, want %v", c.str, value, want)
+		}
+	}
+}
+
+func TestEquals(t *testing.T) {
+	dtstart := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	r1, _ := NewRRule(ROption{Freq: WEEKLY, Byweekday: []Weekday{MO, FR}, Dtstart: dtstart})
+	r2, _ := NewRRule(ROption{Freq: WEEKLY, Interval: 1, Byweekday: []Weekday{FR, MO}, Dtstart: dtstart})
+	r3, _ := NewRRule(ROption{Freq: WEEKLY, Interval: 2, Byweekday: []Weekday{FR, MO}, Dtstart: dtstart})
+	r4, _ := NewRRule(ROption{Freq: WEEKLY, Byweekday: []Weekday{MO, FR},
+		Dtstart: dtstart.In(time.FixedZone("UTC+8", 8*3600))})
+	if !r1.Equals(r2) {
+		t.Errorf("%v.Equals(%v) = false, want true", r1, r2)
+	}
+	if r1.Equals(r3) {
+		t.Errorf("%v.Equals(%v) = true, want false", r1, r3)
+	}
+	if !r1.Equals(r4) {
+		t.Errorf("%v.Equals(%v) = false, want true", r1, r4)
+	}
+}
+
+func TestClone(t *testing.T) {
+	option := ROption{Freq: MONTHLY,
+		Bymonth:   []int{1, 3},
+		Byweekday: []Weekday{MO},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)}
+	clone := option.Clone()
+	clone.Bymonth[0] = 2
+	clone.Byweekday[0] = FR
+	want := "FREQ=MONTHLY;DTSTART=19970902T090000Z;BYMONTH=1,3;BYDAY=MO"
+	if value := option.String(); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestNewRRuleDoesNotAlias(t *testing.T) {
+	option := ROption{Freq: YEARLY,
+		Count:   2,
+		Bymonth: []int{1},
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)}
+	r1, _ := NewRRule(option)
+	option.Bymonth[0] = 3
+	r2, _ := NewRRule(option)
+	want := []time.Time{time.Date(1998, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1999, 1, 2, 9, 0, 0, 0, time.UTC)}
+	if value := r1.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := r2.String(); value != "FREQ=YEARLY;DTSTART=19970902T090000Z;COUNT=2;BYMONTH=3" {
+		t.Errorf("get %v", value)
+	}
+}
+
+func TestBetweenBounds(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	after := time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)
+	before := time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		incStart, incEnd bool
+		want             []time.Time
+	}{
+		{true, false, []time.Time{time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)}},
+		{false, true, []time.Time{time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC)}},
+		{true, true, []time.Time{time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC)}},
+		{false, false, []time.Time{time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)}},
+	}
+	for _, c := range cases {
+		value := r.BetweenBounds(after, before, c.incStart, c.incEnd)
+		if !timesEqual(value, c.want) {
+			t.Errorf("BetweenBounds(%v, %v) = %v, want %v", c.incStart, c.incEnd, value, c.want)
+		}
+	}
+}
+
+func TestBetweenDTStart(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	before := time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)
+	want := []time.Time{r.DTStart(), time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC), before}
+	if value := r.Between(r.DTStart(), before, true); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	want = []time.Time{time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	if value := r.Between(r.DTStart(), before, false); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	// the occurrences after the series start
+	want = []time.Time{time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC), before}
+	if value := r.BetweenBounds(r.DTStart(), before, false, true); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestBetweenLimited(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: SECONDLY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	after := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	before := time.Date(1997, 9, 2, 9, 0, 3, 0, time.UTC)
+	value, truncated := r.BetweenLimited(after, before, true, 2)
+	want := []time.Time{after, time.Date(1997, 9, 2, 9, 0, 1, 0, time.UTC)}
+	if !timesEqual(value, want) || !truncated {
+		t.Errorf("get %v, %v, want %v, true", value, truncated, want)
+	}
+	value, truncated = r.BetweenLimited(after, before, false, 2)
+	want = []time.Time{time.Date(1997, 9, 2, 9, 0, 1, 0, time.UTC), time.Date(1997, 9, 2, 9, 0, 2, 0, time.UTC)}
+	if !timesEqual(value, want) || truncated {
+		t.Errorf("get %v, %v, want %v, false", value, truncated, want)
+	}
+	value, truncated = r.BetweenLimited(after, after.AddDate(1, 0, 0), true, 0)
+	if len(value) != defaultBetweenLimit || !truncated {
+		t.Errorf("get %v occurrences, %v, want %v, true", len(value), truncated, defaultBetweenLimit)
+	}
+}
+
+func TestCountBetween(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	after := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	before := time.Date(1997, 9, 6, 9, 0, 0, 0, time.UTC)
+	if value := r.CountBetween(after, before, true); value != 5 {
+		t.Errorf("get %v, want 5", value)
+	}
+	if value := r.CountBetween(after, before, false); value != 3 {
+		t.Errorf("get %v, want 3", value)
+	}
+	if value := r.CountBetween(time.Date(1997, 8, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC), true); value != 0 {
+		t.Errorf("get %v, want 0", value)
+	}
+}
+
+func TestYearlyByWeekNoAndWeekday(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Count:     4,
+		Byweekno:  []int{20},
+		Byweekday: []Weekday{MO},
+		Dtstart:   time.Date(2019, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2019, 5, 13, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 5, 11, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 5, 17, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 5, 16, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+
+	// the Sunday ending ISO week 20, and the one starting week 20 with WKST=SU
+	option := ROption{Freq: YEARLY,
+		Count:     3,
+		Byweekno:  []int{20},
+		Byweekday: []Weekday{SU},
+		Dtstart:   time.Date(2019, 1, 1, 9, 0, 0, 0, time.UTC)}
+	r, _ = NewRRule(option)
+	want = []time.Time{time.Date(2019, 5, 19, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 5, 17, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 5, 23, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	option.Wkst = SU
+	r, _ = NewRRule(option)
+	want = []time.Time{time.Date(2019, 5, 12, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 5, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 5, 16, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestByWeekNo1Wkst(t *testing.T) {
+	option := ROption{Freq: DAILY,
+		Byweekno: []int{1},
+		Until:    time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+		Dtstart:  time.Date(2019, 12, 1, 9, 0, 0, 0, time.UTC)}
+	weeks := func(starts ...time.Time) []time.Time {
+		result := []time.Time{}
+		for _, start := range starts {
+			for i := 0; i < 7; i++ {
+				result = append(result, start.AddDate(0, 0, i))
+			}
+		}
+		return result
+	}
+	// ISO weeks: 2020 starts on Wednesday, 2021 on Friday and 2022 on Saturday
+	r, _ := NewRRule(option)
+	want := weeks(time.Date(2019, 12, 30, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 3, 9, 0, 0, 0, time.UTC))
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	option.Wkst = SU
+	r, _ = NewRRule(option)
+	want = weeks(time.Date(2019, 12, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC))
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := r.String(); !strings.Contains(value, "WKST=SU") || !strings.Contains(value, "BYWEEKNO=1") {
+		t.Errorf("get %v, want WKST=SU and BYWEEKNO=1", value)
+	}
+	parsed, err := StrToRRule(r.String())
+	if err != nil {
+		t.Fatalf("StrToRRule returned error: %v", err)
+	}
+	if value := parsed.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestStep(t *testing.T) {
+	cases := []struct {
+		str  string
+		want time.Duration
+		ok   bool
+	}{
+		{"FREQ=WEEKLY;INTERVAL=2;DTSTART=19970902T090000Z", 14 * 24 * time.Hour, true},
+		{"FREQ=WEEKLY;BYDAY=FR;DTSTART=19970902T090000Z", 7 * 24 * time.Hour, true},
+		{"FREQ=DAILY;INTERVAL=3;BYHOUR=18;DTSTART=19970902T090000Z", 72 * time.Hour, true},
+		{"FREQ=HOURLY;INTERVAL=6;BYMINUTE=30;DTSTART=19970902T090000Z", 6 * time.Hour, true},
+		{"FREQ=MINUTELY;INTERVAL=15;DTSTART=19970902T090000Z", 15 * time.Minute, true},
+		{"FREQ=SECONDLY;DTSTART=19970902T090000Z", time.Second, true},
+		{"FREQ=MONTHLY;DTSTART=19970902T090000Z", 0, false},
+		{"FREQ=YEARLY;DTSTART=19970902T090000Z", 0, false},
+		{"FREQ=WEEKLY;BYDAY=MO,FR;DTSTART=19970902T090000Z", 0, false},
+		{"FREQ=DAILY;BYDAY=MO;DTSTART=19970902T090000Z", 0, false},
+		{"FREQ=DAILY;BYHOUR=9,17;DTSTART=19970902T090000Z", 0, false},
+		{"FREQ=DAILY;BYMONTH=9;DTSTART=19970902T090000Z", 0, false},
+		{"FREQ=HOURLY;BYHOUR=9;DTSTART=19970902T090000Z", 0, false},
+	}
+	for _, c := range cases {
+		r, err := StrToRRule(c.str)
+		if err != nil {
+			t.Fatalf("StrToRRule(%q) returned error: %v", c.str, err)
+		}
+		if value, ok := r.Step(); value != c.want || ok != c.ok {
+			t.Errorf("%s: get %v, %v, want %v, %v", c.str, value, ok, c.want, c.ok)
+		}
+	}
+	// with IncludeDTStart, a DTSTART which doesn't match the rule comes off the step
+	dtstartCases := []struct {
+		option ROption
+		want   time.Duration
+		ok     bool
+	}{
+		// 2022-01-04 is a Tuesday, 6 days before the first Monday
+		{ROption{Freq: WEEKLY, Byweekday: []Weekday{MO},
+			Dtstart: time.Date(2022, 1, 4, 9, 0, 0, 0, time.UTC)}, 0, false},
+		// 23 hours before the first 9:00
+		{ROption{Freq: DAILY, Byhour: []int{9},
+			Dtstart: time.Date(2022, 1, 4, 10, 0, 0, 0, time.UTC)}, 0, false},
+		{ROption{Freq: WEEKLY, Byweekday: []Weekday{TU},
+			Dtstart: time.Date(2022, 1, 4, 9, 0, 0, 0, time.UTC)}, 7 * 24 * time.Hour, true},
+	}
+	for _, c := range dtstartCases {
+		c.option.IncludeDTStart = true
+		r, _ := NewRRule(c.option)
+		if value, ok := r.Step(); value != c.want || ok != c.ok {
+			t.Errorf("%s: get %v, %v, want %v, %v", c.option.String(), value, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestForEach(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   4,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	value := []time.Time{}
+	r.ForEach(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC), time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		func(dt time.Time) bool {
+			value = append(value, dt)
+			return true
+		})
+	// COUNT ends the iteration before the window does
+	want := []time.Time{time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Until:   time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC),
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	value = []time.Time{}
+	r.ForEach(time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC), time.Date(1997, 9, 20, 0, 0, 0, 0, time.UTC),
+		func(dt time.Time) bool {
+			value = append(value, dt)
+			return true
+		})
+	want = []time.Time{time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+
+	// an infinite rule stops as soon as fn returns false
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	calls := 0
+	r.ForEach(time.Time{}, time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), func(dt time.Time) bool {
+		calls++
+		return calls < 2
+	})
+	if calls != 2 {
+		t.Errorf("get %v calls, want 2", calls)
+	}
+}
+
+func TestIncludeDTStart(t *testing.T) {
+	// 1997-09-02 is a Tuesday
+	option := ROption{Freq: WEEKLY,
+		Count:     3,
+		Byweekday: []Weekday{MO},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)}
+	r, _ := NewRRule(option)
+	want := []time.Time{time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 22, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	option.IncludeDTStart = true
+	r, _ = NewRRule(option)
+	want = []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 15, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	option.Count = 1
+	r, _ = NewRRule(option)
+	if value := r.All(); !timesEqual(value, want[:1]) {
+		t.Errorf("get %v, want %v", value, want[:1])
+	}
+	// a matching DTSTART is not repeated
+	option.Count = 2
+	option.Byweekday = []Weekday{TU}
+	r, _ = NewRRule(option)
+	want = []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestFirst(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	if value, ok := r.First(); !ok || value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	// 1997-09-02 is a Tuesday
+	r, _ = NewRRule(ROption{Freq: WEEKLY,
+		Byweekday: []Weekday{FR},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want = time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC)
+	if value, ok := r.First(); !ok || value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	r, _ = NewRRule(ROption{Freq: YEARLY,
+		Count:      3,
+		Bymonth:    []int{2},
+		Bymonthday: []int{31},
+		Dtstart:    time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if value, ok := r.First(); ok {
+		t.Errorf("get %v, want no occurrence", value)
+	}
+}
+
+func TestLast(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if value, ok := r.Last(); ok {
+		t.Errorf("get %v, want none", value)
+	}
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Count:   3,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)
+	if value, ok := r.Last(); !ok || value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	// 2997-09-02 is a Saturday, the last week of the rule starts on Monday 2997-08-21
+	r, _ = NewRRule(ROption{Freq: WEEKLY,
+		Interval:  3,
+		Byweekday: []Weekday{TU, TH},
+		Until:     time.Date(2997, 9, 2, 9, 0, 0, 0, time.UTC),
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want = time.Date(2997, 8, 24, 9, 0, 0, 0, time.UTC)
+	if value, ok := r.Last(); !ok || value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	r, _ = NewRRule(ROption{Freq: YEARLY,
+		Count:      3,
+		Bymonth:    []int{2},
+		Bymonthday: []int{31},
+		Dtstart:    time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if value, ok := r.Last(); ok {
+		t.Errorf("get %v, want none", value)
+	}
+}
+
+func TestLastMatchesAll(t *testing.T) {
+	nyc, _ := time.LoadLocation("America/New_York")
+	until := time.Date(2003, 3, 9, 2, 30, 0, 0, nyc)
+	options := []ROption{
+		{Freq: DAILY, Interval: 5, Byhour: []int{2, 14}},
+		{Freq: DAILY, Bysetpos: []int{-1}, Byhour: []int{1, 2, 3}},
+		{Freq: DAILY, Byweekday: []Weekday{MO}},
+		{Freq: WEEKLY, Interval: 2},
+		{Freq: WEEKLY, Interval: 4, Wkst: SU, Byweekday: []Weekday{SU, SA}},
+		{Freq: WEEKLY, Byweekday: []Weekday{SA}, Bysetpos: []int{1}},
+		{Freq: MONTHLY, Interval: 5, Bymonthday: []int{31, -1}, Byhour: []int{1, 2, 3}},
+		{Freq: MONTHLY, Byweekday: []Weekday{MO.Nth(1), FR.Nth(-1)}, Bysetpos: []int{-1}},
+		{Freq: YEARLY, Interval: 3, Bymonth: []int{2, 11}, Byweekday: []Weekday{SU.Nth(-1)}},
+		{Freq: YEARLY, Byweekno: []int{1, 53}, Byweekday: []Weekday{MO}},
+	}
+	for _, option := range options {
+		option.Dtstart = time.Date(1997, 9, 2, 2, 30, 0, 0, nyc)
+		option.Until = until
+		r, _ := NewRRule(option)
+		all := r.All()
+		want := all[len(all)-1]
+		if value, ok := r.Last(); !ok || !value.Equal(want) {
+			t.Errorf("%v: get %v, want %v", option, value, want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   3,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)
+	if value, ok := r.Next(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)); !ok || value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value, ok := r.Next(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)); ok {
+		t.Errorf("get %v, want no occurrence", value)
+	}
+}
+
+func TestCountBeforeUntil(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   2,
+		Until:   time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC),
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if ok, last := r.Terminates(); !ok || last != want[1] {
+		t.Errorf("get %v, %v, want true, %v", ok, last, want[1])
+	}
+}
+
+func TestUntilBeforeCount(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   10,
+		Until:   time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if ok, last := r.Terminates(); !ok || last != want[1] {
+		t.Errorf("get %v, %v, want true, %v", ok, last, want[1])
+	}
+}
+
+func TestTerminatesUnbounded(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if ok, last := r.Terminates(); ok || !last.IsZero() {
+		t.Errorf("get %v, %v, want false, zero time", ok, last)
+	}
+}
+
+func TestIsInfinite(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if !r.IsInfinite() {
+		t.Errorf("IsInfinite() = false, want true")
+	}
+	r, _ = NewRRule(ROption{Freq: DAILY, Count: 3,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if r.IsInfinite() {
+		t.Errorf("IsInfinite() = true with COUNT, want false")
+	}
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		Until:   time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)})
+	if r.IsInfinite() {
+		t.Errorf("IsInfinite() = true with UNTIL, want false")
+	}
+}
+
+func TestWeekdayAccessors(t *testing.T) {
+	days := []Weekday{MO, TU, WE, TH, FR, SA, SU}
+	want := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+		time.Friday, time.Saturday, time.Sunday}
+	for i, wday := range days {
+		if value := wday.Nth(-2).Day(); value != want[i] {
+			t.Errorf("get %v, want %v", value, want[i])
+		}
+	}
+	if value := MO.N(); value != 0 {
+		t.Errorf("get %v, want %v", value, 0)
+	}
+	wday := TH.Nth(-1)
+	if value := wday.Nth(2).N(); value != 2 {
+		t.Errorf("get %v, want %v", value, 2)
+	}
+	if value := wday.N(); value != -1 {
+		t.Errorf("get %v, want %v", value, -1)
+	}
+}
+
+func TestWeekdayNextPrevious(t *testing.T) {
+	// 1997-09-02 is a Tuesday
+	from := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		wday           Weekday
+		next, previous time.Time
+	}{
+		{TU, time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC), time.Date(1997, 8, 26, 9, 0, 0, 0, time.UTC)},
+		{WE, time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC), time.Date(1997, 8, 27, 9, 0, 0, 0, time.UTC)},
+		{MO.Nth(-1), time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC), time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)},
+		{SU, time.Date(1997, 9, 7, 9, 0, 0, 0, time.UTC), time.Date(1997, 8, 31, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		if value := c.wday.Next(from); value != c.next {
+			t.Errorf("%v.Next: get %v, want %v", c.wday, value, c.next)
+		}
+		if value := c.wday.Previous(from); value != c.previous {
+			t.Errorf("%v.Previous: get %v, want %v", c.wday, value, c.previous)
+		}
+	}
+}
+
+func TestAllInLocation(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	// 9am in New York across the 2022-03-13 spring forward
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   3,
+		Dtstart: time.Date(2022, 3, 12, 9, 0, 0, 0, newYork)})
+	want := []time.Time{time.Date(2022, 3, 12, 14, 0, 0, 0, time.UTC),
+		time.Date(2022, 3, 13, 13, 0, 0, 0, time.UTC),
+		time.Date(2022, 3, 14, 13, 0, 0, 0, time.UTC)}
+	value := r.AllInLocation(time.UTC)
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	value = r.BetweenInLocation(want[1], want[2], true, time.UTC)
+	if !timesEqual(value, want[1:]) {
+		t.Errorf("get %v, want %v", value, want[1:])
+	}
+	if value := r.AfterInLocation(want[0], false, time.UTC); value != want[1] {
+		t.Errorf("get %v, want %v", value, want[1])
+	}
+	if value := r.BeforeInLocation(want[2], false, time.UTC); value != want[1] {
+		t.Errorf("get %v, want %v", value, want[1])
+	}
+	if value := r.AfterInLocation(want[2], false, time.UTC); !value.IsZero() {
+		t.Errorf("get %v, want zero time", value)
+	}
+}
+
+func TestDSTPolicySpringForward(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	// on 2022-03-13 clocks jump from 02:00 to 03:00, 02:30 doesn't exist
+	cases := []struct {
+		policy DSTPolicy
+		want   []time.Time
+	}{
+		{DSTKeep, []time.Time{time.Date(2022, 3, 12, 7, 30, 0, 0, time.UTC),
+			time.Date(2022, 3, 13, 6, 30, 0, 0, time.UTC),
+			time.Date(2022, 3, 14, 6, 30, 0, 0, time.UTC)}},
+		{DSTSkip, []time.Time{time.Date(2022, 3, 12, 7, 30, 0, 0, time.UTC),
+			time.Date(2022, 3, 14, 6, 30, 0, 0, time.UTC)}},
+		{DSTShiftForward, []time.Time{time.Date(2022, 3, 12, 7, 30, 0, 0, time.UTC),
+			time.Date(2022, 3, 13, 7, 30, 0, 0, time.UTC),
+			time.Date(2022, 3, 14, 6, 30, 0, 0, time.UTC)}},
+	}
+	for _, c := range cases {
+		r, _ := NewRRule(ROption{Freq: DAILY,
+			DSTPolicy: c.policy,
+			Dtstart:   time.Date(2022, 3, 12, 2, 30, 0, 0, newYork),
+			Until:     time.Date(2022, 3, 14, 2, 30, 0, 0, newYork)})
+		value := r.AllInLocation(time.UTC)
+		if !timesEqual(value, c.want) {
+			t.Errorf("policy %v: get %v, want %v", c.policy, value, c.want)
+		}
+	}
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:     2,
+		DSTPolicy: DSTShiftForward,
+		Dtstart:   time.Date(2022, 3, 12, 2, 30, 0, 0, newYork)})
+	if value := r.All()[1]; value.Hour() != 3 || value.Minute() != 30 {
+		t.Errorf("get %v, want 03:30 local", value)
+	}
+	// on 2022-10-02 Lord Howe clocks jump from 02:00 to 02:30
+	lordHowe, _ := time.LoadLocation("Australia/Lord_Howe")
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Count:     2,
+		DSTPolicy: DSTShiftForward,
+		Dtstart:   time.Date(2022, 10, 1, 2, 15, 0, 0, lordHowe)})
+	want := time.Date(2022, 10, 1, 15, 45, 0, 0, time.UTC)
+	if value := r.All()[1]; !value.Equal(want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestDSTPolicyFallBack(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	// on 2022-11-06 clocks go back from 02:00 to 01:00, 01:30 exists twice
+	want := []time.Time{time.Date(2022, 11, 5, 5, 30, 0, 0, time.UTC),
+		time.Date(2022, 11, 6, 5, 30, 0, 0, time.UTC),
+		time.Date(2022, 11, 7, 6, 30, 0, 0, time.UTC)}
+	for _, policy := range []DSTPolicy{DSTKeep, DSTSkip, DSTShiftForward} {
+		r, _ := NewRRule(ROption{Freq: DAILY,
+			Count:     3,
+			DSTPolicy: policy,
+			Dtstart:   time.Date(2022, 11, 5, 1, 30, 0, 0, newYork)})
+		value := r.AllInLocation(time.UTC)
+		if !timesEqual(value, want) {
+			t.Errorf("policy %v: get %v, want %v", policy, value, want)
+		}
+	}
+}
+
+func TestNth(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: WEEKLY,
+		Count:     4,
+		Byweekday: []Weekday{TU, TH},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC)
+	if value, ok := r.Nth(2); !ok || value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	want = time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	if value, ok := r.Nth(0); !ok || value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	for _, i := range []int{-1, 4} {
+		if value, ok := r.Nth(i); ok {
+			t.Errorf("Nth(%v) = %v, want no occurrence", i, value)
+		}
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: WEEKLY,
+		Byweekday: []Weekday{TU, TH},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if value, ok := r.IndexOf(time.Date(1997, 9, 11, 9, 0, 0, 0, time.UTC)); !ok || value != 3 {
+		t.Errorf("get %v, want %v", value, 3)
+	}
+	if value, ok := r.IndexOf(time.Date(1997, 9, 11, 10, 0, 0, 0, time.UTC)); ok {
+		t.Errorf("get %v, want not found", value)
+	}
+	if value, ok := r.IndexOf(time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)); ok {
+		t.Errorf("get %v, want not found", value)
+	}
+}
+
+func BenchmarkYearlyByMonth(b *testing.B) {
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Bymonth:    []int{2},
+		Bymonthday: []int{29},
+		Dtstart:    time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		Until:      time.Date(2097, 9, 2, 9, 0, 0, 0, time.UTC)})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.All()
+	}
+}
+
+func TestMonthlyNthWeekdayFastPath(t *testing.T) {
+	// the days of MONTHLY ordinal weekdays are computed, the ones of YEARLY are filtered
+	dtstart := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	until := time.Date(2007, 9, 2, 9, 0, 0, 0, time.UTC)
+	monthly, _ := NewRRule(ROption{Freq: MONTHLY,
+		Bymonth:   []int{1, 6},
+		Byweekday: []Weekday{TU.Nth(2), FR.Nth(-1), MO.Nth(5)},
+		Dtstart:   dtstart,
+		Until:     until})
+	yearly, _ := NewRRule(ROption{Freq: YEARLY,
+		Bymonth:   []int{1, 6},
+		Byweekday: []Weekday{TU.Nth(2), FR.Nth(-1), MO.Nth(5)},
+		Dtstart:   dtstart,
+		Until:     until})
+	want := yearly.All()
+	if value := monthly.All(); len(want) == 0 || !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestNthWeekdayOutOfRange(t *testing.T) {
+	// NewRRule doesn't check the ordinals, the days of too large ones don't exist
+	dtstart := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	cases := []ROption{
+		{Freq: MONTHLY, Count: 1, Byweekday: []Weekday{MO.Nth(-6)}, Dtstart: dtstart},
+		{Freq: MONTHLY, Count: 1, Byweekday: []Weekday{MO.Nth(6)}, Dtstart: dtstart},
+		{Freq: YEARLY, Count: 1, Byweekday: []Weekday{MO.Nth(60)}, Dtstart: dtstart},
+		{Freq: YEARLY, Count: 1, Byweekday: []Weekday{MO.Nth(-60)}, Dtstart: dtstart},
+		{Freq: YEARLY, Count: 1, Bymonth: []int{2}, Byweekday: []Weekday{MO.Nth(-6)}, Dtstart: dtstart},
+	}
+	for _, option := range cases {
+		r, _ := NewRRule(option)
+		if value := r.Between(dtstart, dtstart.AddDate(20, 0, 0), true); len(value) != 0 {
+			t.Errorf("%v: get %v, want no occurrence", option.String(), value)
+		}
+	}
+	// the ones in range still apply
+	r, _ := NewRRule(ROption{Freq: MONTHLY, Count: 1, Byweekday: []Weekday{MO.Nth(-6), TU.Nth(-1)}, Dtstart: dtstart})
+	want := []time.Time{time.Date(1997, 9, 30, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func BenchmarkMonthlyNthWeekday(b *testing.B) {
+	r, _ := NewRRule(ROption{Freq: MONTHLY,
+		Byweekday: []Weekday{TU.Nth(2)},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		Until:     time.Date(2047, 9, 2, 9, 0, 0, 0, time.UTC)})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.All()
+	}
+}
+
+func TestSubDailyByHourJump(t *testing.T) {
+	// the occurrences in the window are the ones of the rule without it, in the window
+	dtstart := time.Date(1997, 9, 1, 8, 3, 5, 0, time.UTC)
+	until := time.Date(1997, 9, 4, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		freq     Frequency
+		interval int
+		byhour   []int
+		byminute []int
+	}{
+		{MINUTELY, 7, []int{9, 17}, nil},
+		{MINUTELY, 1, []int{23}, []int{0, 59}},
+		{SECONDLY, 450, []int{10, 11, 20}, nil},
+		{SECONDLY, 13, []int{9}, []int{5, 30}},
+	}
+	for _, c := range cases {
+		r, _ := NewRRule(ROption{Freq: c.freq, Interval: c.interval,
+			Byhour: c.byhour, Byminute: c.byminute, Dtstart: dtstart, Until: until})
+		all, _ := NewRRule(ROption{Freq: c.freq, Interval: c.interval, Dtstart: dtstart, Until: until})
+		want := []time.Time{}
+		for _, dt := range all.All() {
+			if contains(c.byhour, dt.Hour()) && (len(c.byminute) == 0 || contains(c.byminute, dt.Minute())) {
+				want = append(want, dt)
+			}
+		}
+		if value := r.All(); len(want) == 0 || !timesEqual(value, want) {
+			t.Errorf("%v: get %v occurrences, want %v", r, len(value), len(want))
+		}
+	}
+}
+
+func BenchmarkMinutelyByHour(b *testing.B) {
+	r, _ := NewRRule(ROption{Freq: MINUTELY,
+		Byhour:  []int{9, 10, 11},
+		Dtstart: time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC),
+		Until:   time.Date(1997, 10, 1, 0, 0, 0, 0, time.UTC)})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.All()
+	}
+}
+
+func BenchmarkSecondlyByHour(b *testing.B) {
+	r, _ := NewRRule(ROption{Freq: SECONDLY,
+		Interval: 900,
+		Byhour:   []int{9, 10, 11, 12, 13, 14, 15, 16},
+		Dtstart:  time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC),
+		Until:    time.Date(1997, 10, 1, 0, 0, 0, 0, time.UTC)})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.All()
+	}
+}
+
+func BenchmarkContainsHourly(b *testing.B) {
+	r, _ := NewRRule(ROption{Freq: HOURLY,
+		Interval: 5,
+		Dtstart:  time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC)})
+	dt := time.Date(2097, 9, 1, 20, 0, 0, 0, time.UTC)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Contains(dt)
+	}
+}
+
+func TestOnDate(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: HOURLY,
+		Interval: 8,
+		Dtstart:  time.Date(1997, 9, 2, 1, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 3, 1, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 17, 0, 0, 0, time.UTC)}
+	value := r.OnDate(time.Date(1997, 9, 3, 0, 0, 0, 0, time.UTC), time.UTC)
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	// in New York 1997-09-03 runs from 04:00 UTC to 04:00 UTC the next day
+	newYork, _ := time.LoadLocation("America/New_York")
+	want = []time.Time{time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 17, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 4, 1, 0, 0, 0, time.UTC)}
+	value = r.OnDate(time.Date(1997, 9, 3, 0, 0, 0, 0, time.UTC), newYork)
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := r.OnDate(time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC), time.UTC); len(value) != 0 {
+		t.Errorf("get %v, want none", value)
+	}
+}
+
+func TestDates(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: HOURLY,
+		Interval: 5,
+		Dtstart:  time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	// occurrences at 9:00, 14:00, 19:00 UTC on 09-02, then 0:00, 5:00, 10:00 and 15:00 UTC on 09-03,
+	// 15:00 UTC being midnight the next day in Tokyo
+	want := []time.Time{time.Date(1997, 9, 2, 0, 0, 0, 0, tokyo),
+		time.Date(1997, 9, 3, 0, 0, 0, 0, tokyo),
+		time.Date(1997, 9, 4, 0, 0, 0, 0, tokyo)}
+	value := r.Dates(time.Date(1997, 9, 2, 0, 0, 0, 0, time.UTC), time.Date(1997, 9, 3, 15, 0, 0, 0, time.UTC), tokyo)
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	want = []time.Time{time.Date(1997, 9, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 0, 0, 0, 0, time.UTC)}
+	value = r.Dates(time.Date(1997, 9, 2, 0, 0, 0, 0, time.UTC), time.Date(1997, 9, 3, 12, 0, 0, 0, time.UTC), time.UTC)
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestDebugExpand(t *testing.T) {
+	// last weekday of the month
+	r, _ := NewRRule(ROption{Freq: MONTHLY,
+		Byweekday: []Weekday{MO, TU, WE, TH, FR},
+		Bysetpos:  []int{-1},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	value := r.DebugExpand(time.Date(1997, 10, 15, 0, 0, 0, 0, time.UTC))
+	if len(value) != 23 {
+		t.Fatalf("get %v candidates, want 23", len(value))
+	}
+	want := []time.Time{time.Date(1997, 10, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 10, 31, 9, 0, 0, 0, time.UTC)}
+	if value[0] != want[0] || value[len(value)-1] != want[1] {
+		t.Errorf("get %v ... %v, want %v ... %v", value[0], value[len(value)-1], want[0], want[1])
+	}
+	if last, _ := r.Nth(1); last != want[1] {
+		t.Errorf("get %v, want %v", last, want[1])
+	}
+}
+
+func TestDebugExpandWeekly(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: WEEKLY,
+		Byweekday: []Weekday{MO, SU},
+		Byhour:    []int{9, 18},
+		Wkst:      SU,
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	// the week of Wednesday 1997-12-31 starts on Sunday 1997-12-28
+	value := r.DebugExpand(time.Date(1997, 12, 31, 0, 0, 0, 0, time.UTC))
+	want := []time.Time{time.Date(1997, 12, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 12, 28, 18, 0, 0, 0, time.UTC),
+		time.Date(1997, 12, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 12, 29, 18, 0, 0, 0, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestNextN(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 6, 9, 0, 0, 0, time.UTC)}
+	value := r.NextN(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC), 3)
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Count:   4,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	value = r.NextN(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC), 3)
+	if !timesEqual(value, want[:2]) {
+		t.Errorf("get %v, want %v", value, want[:2])
+	}
+	if value = r.NextN(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC), 0); len(value) != 0 {
+		t.Errorf("get %v, want none", value)
+	}
+}
+
+func TestAllAfter(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: WEEKLY,
+		Interval: 2,
+		Dtstart:  time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	// a rule starting on the anchor would give 1997-09-09 and 1997-09-23
+	want := []time.Time{time.Date(1997, 9, 16, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 30, 9, 0, 0, 0, time.UTC)}
+	value := r.AllAfter(time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC), true, 2)
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	value = r.AllAfter(want[0], true, 1)
+	if !timesEqual(value, want[:1]) {
+		t.Errorf("get %v, want %v", value, want[:1])
+	}
+	value = r.AllAfter(want[0], false, 1)
+	if !timesEqual(value, want[1:]) {
+		t.Errorf("get %v, want %v", value, want[1:])
+	}
+}
+
+func TestNegativeInterval(t *testing.T) {
+	option := ROption{Freq: DAILY, Interval: -1,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)}
+	if _, err := NewRRule(option); err == nil {
+		t.Errorf("NewRRule = nil error, want error")
+	}
+	if err := option.Validate(); err == nil || !strings.Contains(err.Error(), "INTERVAL") {
+		t.Errorf("get %v, want error naming INTERVAL", err)
+	}
+}
+
+func TestZeroRRuleTerminates(t *testing.T) {
+	r := RRule{}
+	if value := r.All(); len(value) != 0 {
+		t.Errorf("get %v, want none", value)
+	}
+}
+
+func TestYearlyByNegativeWeekNo(t *testing.T) {
+	// 2020 and 2026 have 53 ISO weeks
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Count:     4,
+		Byweekno:  []int{-1},
+		Byweekday: []Weekday{MO},
+		Dtstart:   time.Date(2019, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2019, 12, 23, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 12, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 12, 27, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 12, 26, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	r, _ = NewRRule(ROption{Freq: YEARLY,
+		Count:     2,
+		Byweekno:  []int{-1},
+		Byweekday: []Weekday{MO},
+		Dtstart:   time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want = []time.Time{time.Date(2026, 12, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2027, 12, 27, 9, 0, 0, 0, time.UTC)}
+	value = r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestDailyByNegativeWeekNoAcrossYear(t *testing.T) {
+	// week 53 of 2020 is 2020-12-28 to 2021-01-03
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Byweekno: []int{-1},
+		Dtstart:  time.Date(2020, 12, 20, 9, 0, 0, 0, time.UTC),
+		Until:    time.Date(2021, 1, 10, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{}
+	for i := 0; i < 7; i++ {
+		want = append(want, time.Date(2020, 12, 28+i, 9, 0, 0, 0, time.UTC))
+	}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestDailyByWeekNoMinus53(t *testing.T) {
+	// week -53 of 2020 is its week 1, 2019-12-30 to 2020-01-05
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Byweekno: []int{-53},
+		Dtstart:  time.Date(2019, 12, 20, 9, 0, 0, 0, time.UTC),
+		Until:    time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{}
+	for i := 0; i < 7; i++ {
+		want = append(want, time.Date(2019, 12, 30+i, 9, 0, 0, 0, time.UTC))
+	}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestContains(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: WEEKLY,
+		Count:     4,
+		Byweekday: []Weekday{TU, TH},
+		Dtstart:   time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	cases := []struct {
+		t    time.Time
+		want bool
+	}{
+		{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(1997, 9, 11, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(1997, 9, 11, 9, 0, 0, 500, time.UTC), true},
+		{time.Date(1997, 9, 11, 11, 0, 0, 0, time.FixedZone("+02:00", 2*3600)), true},
+		{time.Date(1997, 9, 11, 9, 0, 1, 0, time.UTC), false},
+		{time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(1997, 9, 16, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC), false},
+	}
+	for _, c := range cases {
+		if value := r.Contains(c.t); value != c.want {
+			t.Errorf("Contains(%v) = %v, want %v", c.t, value, c.want)
+		}
+	}
+}
+
+func TestContainsSkipsPeriods(t *testing.T) {
+	nyc, _ := time.LoadLocation("America/New_York")
+	options := []ROption{
+		{Freq: DAILY, Interval: 5, Byhour: []int{2, 14}},
+		{Freq: WEEKLY, Interval: 3, Wkst: SU, Byweekday: []Weekday{SU, SA}},
+		{Freq: HOURLY, Interval: 5},
+		{Freq: HOURLY, Interval: 7, Byhour: []int{1, 2, 3, 14}},
+		{Freq: MINUTELY, Interval: 45, Until: time.Date(2030, 1, 1, 0, 0, 0, 0, nyc)},
+		{Freq: SECONDLY, Interval: 3600 * 7},
+		{Freq: MONTHLY, Interval: 11, Bymonthday: []int{8, -22}, Byhour: []int{2, 14}},
+		{Freq: YEARLY, Interval: 2, Bymonth: []int{3}, Byweekday: []Weekday{SU.Nth(2)}},
+	}
+	after := time.Date(2003, 3, 8, 0, 0, 0, 0, nyc)
+	before := time.Date(2003, 3, 11, 0, 0, 0, 0, nyc)
+	for _, option := range options {
+		option.Dtstart = time.Date(1997, 9, 2, 2, 30, 0, 0, nyc)
+		r, _ := NewRRule(option)
+		occurrences := r.Between(after, before, true)
+		if len(occurrences) == 0 {
+			t.Errorf("%v: no occurrence between %v and %v", option.String(), after, before)
+		}
+		for _, dt := range occurrences {
+			if !r.Contains(dt) {
+				t.Errorf("%v: Contains(%v) = false, want true", option.String(), dt)
+			}
+			if next := dt.Add(time.Hour); next.Before(before) && r.Contains(next) != timeContains(occurrences, next) {
+				t.Errorf("%v: Contains(%v) = %v, want %v", option.String(), next, !timeContains(occurrences, next), timeContains(occurrences, next))
+			}
+		}
+	}
+}
+
+func TestAllUnix(t *testing.T) {
+	nyc, _ := time.LoadLocation("America/New_York")
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   3,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, nyc)})
+	want := []int64{}
+	for _, dt := range r.All() {
+		want = append(want, dt.Unix())
+	}
+	value := r.AllUnix()
+	if len(value) != 3 || value[0] != 873205200 || value[0] != want[0] || value[1] != want[1] || value[2] != want[2] {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestAllUntil(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Count:   1000,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)}
+	if value := r.AllUntil(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Count:   2,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if value := r.AllUntil(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)); !timesEqual(value, want[:2]) {
+		t.Errorf("get %v, want %v", value, want[:2])
+	}
+}
+
+func TestAllWithContext(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if value, err := r.AllWithContext(ctx); err != context.Canceled || len(value) != 0 {
+		t.Errorf("get %v, %v, want %v", value, err, context.Canceled)
+	}
+	r, _ = NewRRule(ROption{Freq: DAILY, Count: 2,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	if value, err := r.AllWithContext(context.Background()); err != nil || !timesEqual(value, want) {
+		t.Errorf("get %v, %v, want %v", value, err, want)
+	}
+	// a rule which never matches stops within its periods
+	r, _ = NewRRule(ROption{Freq: MINUTELY, Bymonth: []int{2}, Bymonthday: []int{30},
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if value, err := r.AllWithContext(ctx); err != context.DeadlineExceeded || len(value) != 0 {
+		t.Errorf("get %v, %v, want %v", value, err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("AllWithContext returned after %v, want it to stop at the deadline", elapsed)
+	}
+}
+
+func TestAllWithLimit(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	value, more := r.AllWithLimit(2)
+	if !timesEqual(value, want) || !more {
+		t.Errorf("get %v, %v, want %v, true", value, more, want)
+	}
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Count:   2,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	value, more = r.AllWithLimit(2)
+	if !timesEqual(value, want) || more {
+		t.Errorf("get %v, %v, want %v, false", value, more, want)
+	}
+	value, more = r.AllWithLimit(5)
+	if !timesEqual(value, want) || more {
+		t.Errorf("get %v, %v, want %v, false", value, more, want)
+	}
+	value, more = r.AllWithLimit(0)
+	if len(value) != 0 || !more {
+		t.Errorf("get %v, %v, want none, true", value, more)
+	}
+}
+
+func TestNewRRuleFrom(t *testing.T) {
+	dtstart := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	r, err := NewRRuleFrom(ROption{Freq: DAILY, Count: 2}, dtstart)
+	if err != nil {
+		t.Fatalf("NewRRuleFrom returned error: %v", err)
+	}
+	want := []time.Time{dtstart, time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	// the DTSTART of the option wins
+	r, _ = NewRRuleFrom(ROption{Freq: DAILY, Count: 1, Dtstart: want[1]}, dtstart)
+	if value := r.All(); !timesEqual(value, want[1:]) {
+		t.Errorf("get %v, want %v", value, want[1:])
+	}
+	if _, err := NewRRuleFrom(ROption{Freq: DAILY, Count: 2}, time.Time{}); err == nil {
+		t.Errorf("NewRRuleFrom without DTSTART = nil error, want error")
+	}
+}
+
+func TestDTStart(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 500, time.UTC)})
+	want := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	if value := r.DTStart(); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	r, _ = NewRRule(ROption{Freq: DAILY})
+	if value := r.DTStart(); value.IsZero() {
+		t.Errorf("get zero time, want the construction time")
+	}
+}
@@ -1,45 +1,238 @@
 package rrule
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"sort"
 	"time"
 )
 
 // Set allows more complex recurrence setups, mixing multiple rules, dates, exclusion rules, and exclusion dates
 type Set struct {
-	rrule  []*RRule
-	rdate  []time.Time
-	exrule []*RRule
-	exdate []time.Time
+	// dtstart is the DTSTART shared by the rules of the set, if set with SetDTStart
+	dtstart time.Time
+	rrule   []*RRule
+	rdate   []time.Time
+	// rday holds the RDATE values of DATE type, as UTC midnights, so that they are
+	// emitted as dates, they are in rdate too
+	rday []time.Time
+	// rperiod holds the RDATE values of PERIOD type, their starts are occurrences of the set
+	rperiod []Occurrence
+	exrule  []*RRule
+	exdate  []time.Time
+	// exday holds the EXDATE values of DATE type, as UTC midnights,
+	// they exclude every occurrence on their day
+	exday []time.Time
+	// merged holds the sets merged into the set which have exclusions, see Merge,
+	// they keep their exclusions apart from the ones of the other sets
+	merged []*Set
+	// XProps holds vendor extension lines (ex. "X-WR-TIMEZONE:Europe/Paris"),
+	// they are emitted verbatim after the recurrence lines
+	XProps []string
 }
 
-// Recurrence returns a slice of all the recurrence rules for a set
+// Recurrence returns a slice of all the recurrence rules for a set.
+// The lines come in a fixed order: the rrules, each preceded by its DTSTART line
+// if it's in a named time zone, the RDATEs, the exrules, the EXDATEs, then XProps.
+// If the set has a DTSTART, see SetDTStart, it comes once as the first line instead,
+// and the rrules and exrules have no DTSTART of their own.
+// RDATEs and EXDATEs are emitted in chronological order, without duplicates,
+// in their time zone, or as dates if they are of DATE type, so that the set parsed back
+// from the lines is the same.
+// The lines of the sets merged with exclusions, see Merge, come after the EXDATEs,
+// the set parsed back from them applies all the exclusions to all the occurrences.
 func (set *Set) Recurrence() []string {
 	res := []string{}
+	set.eachLine(func(line string) error {
+		res = append(res, line)
+		return nil
+	})
+	return res
+}
+
+// eachLine calls fn with each line of the recurrence of the set, in order,
+// until fn returns an error.
+func (set *Set) eachLine(fn func(line string) error) error {
+	if !set.dtstart.IsZero() {
+		if err := fn(set.dtstartLine()); err != nil {
+			return err
+		}
+	}
+	if err := set.eachBodyLine(set, fn); err != nil {
+		return err
+	}
+	for _, line := range set.XProps {
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachBodyLine calls fn with the lines of the rules and dates of the set,
+// then of the sets merged into it, with the rules written by the ruleLines of top.
+func (set *Set) eachBodyLine(top *Set, fn func(line string) error) error {
 	for _, item := range set.rrule {
-		res = append(res, fmt.Sprintf("RRULE:%s", item))
+		for _, line := range top.ruleLines(item, PropRRule) {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
 	}
-	for _, item := range set.rdate {
-		res = append(res, fmt.Sprintf("RDATE:%s", timeToStr(item)))
+	for _, item := range uniqueSortedTimes(set.rdate) {
+		line := dateLine(PropRDate, item)
+		if timeContains(set.rday, item) {
+			line = fmt.Sprintf("%s;VALUE=DATE:%s", PropRDate, item.Format(DateFormat))
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	for _, item := range set.rperiod {
+		if err := fn(fmt.Sprintf("%s;VALUE=PERIOD:%s/%s", PropRDate, timeToStr(item.Start), timeToStr(item.End))); err != nil {
+			return err
+		}
 	}
 	for _, item := range set.exrule {
-		res = append(res, fmt.Sprintf("EXRULE:%s", item))
+		for _, line := range top.ruleLines(item, PropExRule) {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
 	}
-	for _, item := range set.exdate {
-		res = append(res, fmt.Sprintf("EXDATE:%s", timeToStr(item)))
+	for _, item := range uniqueSortedTimes(set.exdate) {
+		if err := fn(dateLine(PropExDate, item)); err != nil {
+			return err
+		}
+	}
+	for _, item := range uniqueSortedTimes(set.exday) {
+		if err := fn(fmt.Sprintf("%s;VALUE=DATE:%s", PropExDate, item.Format(DateFormat))); err != nil {
+			return err
+		}
+	}
+	for _, member := range set.merged {
+		if err := member.eachBodyLine(top, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ruleLines returns the lines of a rule of the set as a property with the given name,
+// without DTSTART if the set has one.
+func (set *Set) ruleLines(r *RRule, name Property) []string {
+	if set.dtstart.IsZero() {
+		return r.OrigOptions.rfcLines(name)
+	}
+	return []string{fmt.Sprintf("%s:%s", name, r.OrigOptions.rruleString(nil, false))}
+}
+
+// dtstartLine returns the DTSTART line of a set with a DTSTART, a date if its rules are AllDay.
+func (set *Set) dtstartLine() string {
+	option := ROption{Dtstart: set.dtstart, AllDay: set.allDay()}
+	if line := option.dtstartLine(); line != "" {
+		return line
+	}
+	return dateLine(PropDTStart, set.dtstart)
+}
+
+// allDay reports whether the set has rrules and all of them are AllDay.
+func (set *Set) allDay() bool {
+	for _, r := range set.rrule {
+		if !r.OrigOptions.AllDay {
+			return false
+		}
+	}
+	return len(set.rrule) != 0
+}
+
+// dateLine returns the line of an RDATE or EXDATE property with the given name,
+// with a TZID parameter if t is in a named time zone, so that it keeps its zone.
+func dateLine(name Property, t time.Time) string {
+	if tzid := tzidOf(t); tzid != "" {
+		return fmt.Sprintf("%s;TZID=%s:%s", name, tzid, t.Format(LocalDateTimeFormat))
+	}
+	return fmt.Sprintf("%s:%s", name, timeToStr(t))
+}
+
+// WriteTo writes the recurrence lines of the set to w, each one terminated by a newline,
+// without building them all in memory. It implements io.WriterTo.
+func (set *Set) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	err := set.eachLine(func(line string) error {
+		n, err := io.WriteString(w, line+"\n")
+		total += int64(n)
+		return err
+	})
+	return total, err
+}
+
+// Normalize sorts the rdates and the exdates of the set chronologically
+// and removes the duplicate ones, compared as instants.
+func (set *Set) Normalize() {
+	set.rdate = uniqueSortedTimes(set.rdate)
+	set.rday = uniqueSortedTimes(set.rday)
+	set.exdate = uniqueSortedTimes(set.exdate)
+	set.exday = uniqueSortedTimes(set.exday)
+	for _, member := range set.merged {
+		member.Normalize()
 	}
-	return res
 }
 
 // RRule include the given rrule instance in the recurrence set generation.
+// If the set has a DTSTART, see SetDTStart, a copy of rrule with this DTSTART is included.
 func (set *Set) RRule(rrule *RRule) {
+	if !set.dtstart.IsZero() {
+		rrule = rrule.withDTStart(set.dtstart)
+	}
 	set.rrule = append(set.rrule, rrule)
 }
 
-// GetRRule return the rrules in the set
+// SetDTStart sets the DTSTART of the set, shared by all its rules: the rrules and exrules
+// of the set, and the ones included afterwards, are replaced by copies with this DTSTART.
+// It's truncated to the second, as by NewRRule. Recurrence then emits it once as the first line,
+// as a date, ex. "DTSTART;VALUE=DATE:20220101", if the rrules of the set are AllDay.
+func (set *Set) SetDTStart(dtstart time.Time) {
+	set.dtstart = dtstart.Truncate(time.Second)
+	for i, r := range set.rrule {
+		set.rrule[i] = r.withDTStart(set.dtstart)
+	}
+	for i, r := range set.exrule {
+		set.exrule[i] = r.withDTStart(set.dtstart)
+	}
+	for _, member := range set.merged {
+		member.SetDTStart(dtstart)
+		// the DTSTART line is emitted by the set only
+		member.dtstart = time.Time{}
+	}
+}
+
+// DTStart returns the DTSTART of the set, if set with SetDTStart,
+// otherwise the earliest DTSTART of the rrules in the set,
+// or time.Time's zero value if it has none.
+func (set *Set) DTStart() time.Time {
+	if !set.dtstart.IsZero() {
+		return set.dtstart
+	}
+	result := time.Time{}
+	for _, r := range set.rrule {
+		if result.IsZero() || r.dtstart.Before(result) {
+			result = r.dtstart
+		}
+	}
+	for _, member := range set.merged {
+		if dtstart := member.DTStart(); !dtstart.IsZero() && (result.IsZero() || dtstart.Before(result)) {
+			result = dtstart
+		}
+	}
+	return result
+}
+
+// GetRRule return the rrules in the set, as a copy of the list of the set.
+// A set can be edited by building a new one from the returned parts.
 func (set *Set) GetRRule() []*RRule {
-	return set.rrule
+	return append([]*RRule(nil), set.rrule...)
 }
 
 // RDate include the given datetime instance in the recurrence set generation.
@@ -47,21 +240,43 @@ func (set *Set) RDate(rdate time.Time) {
 	set.rdate = append(set.rdate, rdate)
 }
 
-// GetRDate returns explicitly added dates (rdates) in the set
+// RDateDay includes the day of date in the recurrence set generation, at its UTC midnight,
+// as an RDATE of DATE type, ex. "RDATE;VALUE=DATE:20220301".
+func (set *Set) RDateDay(date time.Time) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	set.rdate = append(set.rdate, day)
+	set.rday = append(set.rday, day)
+}
+
+// GetRDate returns explicitly added dates (rdates) in the set, as a copy
 func (set *Set) GetRDate() []time.Time {
-	return set.rdate
+	return append([]time.Time(nil), set.rdate...)
+}
+
+// RPeriod include the start of the given period in the recurrence set generation,
+// the period is kept as an RDATE of PERIOD value type.
+func (set *Set) RPeriod(period Occurrence) {
+	set.rperiod = append(set.rperiod, period)
+}
+
+// GetRPeriod returns the periods added to the set, as a copy
+func (set *Set) GetRPeriod() []Occurrence {
+	return append([]Occurrence(nil), set.rperiod...)
 }
 
 // ExRule include the given rrule instance in the recurrence set exclusion list.
 // Dates which are part of the given recurrence rules will not be generated,
 // even if some inclusive rrule or rdate matches them.
 func (set *Set) ExRule(exrule *RRule) {
+	if !set.dtstart.IsZero() {
+		exrule = exrule.withDTStart(set.dtstart)
+	}
 	set.exrule = append(set.exrule, exrule)
 }
 
-// GetExRule returns exclusion rrules list from in the set
+// GetExRule returns exclusion rrules list from in the set, as a copy
 func (set *Set) GetExRule() []*RRule {
-	return set.exrule
+	return append([]*RRule(nil), set.exrule...)
 }
 
 // ExDate include the given datetime instance in the recurrence set exclusion list.
@@ -71,9 +286,22 @@ func (set *Set) ExDate(exdate time.Time) {
 	set.exdate = append(set.exdate, exdate)
 }
 
-// GetExDate returns explicitly excluded dates (exdates) in the set
+// GetExDate returns explicitly excluded dates (exdates) in the set, as a copy
 func (set *Set) GetExDate() []time.Time {
-	return set.exdate
+	return append([]time.Time(nil), set.exdate...)
+}
+
+// ExDateDay excludes every occurrence on the day of date, whatever its time,
+// as an EXDATE of DATE type, ex. "EXDATE;VALUE=DATE:20220301".
+// An occurrence is on that day if its date in its own time zone,
+// the DTSTART zone for occurrences of a rule, is the date of date.
+func (set *Set) ExDateDay(date time.Time) {
+	set.exday = append(set.exday, time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+// GetExDateDay returns the excluded days of the set, as UTC midnights, as a copy.
+func (set *Set) GetExDateDay() []time.Time {
+	return append([]time.Time(nil), set.exday...)
 }
 
 type genItem struct {
@@ -94,24 +322,52 @@ func addGenList(genList *[]genItem, next Next) {
 	}
 }
 
-// Iterator returns an iterator for rrule.Set
+// Iterator returns an iterator for rrule.Set.
+// It lazily merges the occurrences of the rrules and the rdates in order,
+// skipping the ones of the exrules and the exdates, so it can be used
+// to take a prefix of a set with unbounded rules.
+// Iterating doesn't modify the set, so a set can be read from several goroutines
+// as long as none of them modifies it.
 func (set *Set) Iterator() (next func() (time.Time, bool)) {
+	return set.iteratorWithContext(context.Background())
+}
+
+// iteratorWithContext is like Iterator, the iterator ends when ctx is done.
+func (set *Set) iteratorWithContext(ctx context.Context) Next {
 	rlist := []genItem{}
 	exlist := []genItem{}
 
-	sort.Sort(timeSlice(set.rdate))
-	addGenList(&rlist, timeSliceIterator(set.rdate))
+	// sort copies, the set may be iterated concurrently
+	rdate := append([]time.Time(nil), set.rdate...)
+	sort.Sort(timeSlice(rdate))
+	addGenList(&rlist, timeSliceIterator(rdate))
+	if len(set.rperiod) != 0 {
+		starts := make([]time.Time, len(set.rperiod))
+		for i, period := range set.rperiod {
+			starts[i] = period.Start
+		}
+		sort.Sort(timeSlice(starts))
+		addGenList(&rlist, timeSliceIterator(starts))
+	}
 	for _, r := range set.rrule {
-		addGenList(&rlist, r.Iterator())
+		addGenList(&rlist, r.iteratorWithContext(ctx))
+	}
+	for _, member := range set.merged {
+		addGenList(&rlist, member.iteratorWithContext(ctx))
 	}
 	sort.Sort(genItemSlice(rlist))
 
-	sort.Sort(timeSlice(set.exdate))
-	addGenList(&exlist, timeSliceIterator(set.exdate))
+	exdate := append([]time.Time(nil), set.exdate...)
+	sort.Sort(timeSlice(exdate))
+	addGenList(&exlist, timeSliceIterator(exdate))
 	for _, r := range set.exrule {
-		addGenList(&exlist, r.Iterator())
+		addGenList(&exlist, r.iteratorWithContext(ctx))
 	}
 	sort.Sort(genItemSlice(exlist))
+	exdays := map[time.Time]bool{}
+	for _, day := range set.exday {
+		exdays[day] = true
+	}
 
 	lastdt := time.Time{}
 	return func() (time.Time, bool) {
@@ -132,7 +388,8 @@ func (set *Set) Iterator() (next func() (time.Time, bool)) {
 					sort.Sort(genItemSlice(exlist))
 				}
 				lastdt = dt
-				if len(exlist) == 0 || !dt.Equal(exlist[0].dt) {
+				if (len(exlist) == 0 || !dt.Equal(exlist[0].dt)) &&
+					!exdays[time.Date(dt.Year(), dt.Month(), dt.Day(), 0, 0, 0, 0, time.UTC)] {
 					return dt, true
 				}
 			}
@@ -141,11 +398,33 @@ func (set *Set) Iterator() (next func() (time.Time, bool)) {
 	}
 }
 
+// IsInfinite reports whether one of the rrules of the set is infinite, see RRule.IsInfinite,
+// so that All would not return. Exclusions don't bound the set.
+func (set *Set) IsInfinite() bool {
+	for _, r := range set.rrule {
+		if r.IsInfinite() {
+			return true
+		}
+	}
+	for _, member := range set.merged {
+		if member.IsInfinite() {
+			return true
+		}
+	}
+	return false
+}
+
 // All returns all occurrences of the rrule.Set.
 func (set *Set) All() []time.Time {
 	return all(set.Iterator())
 }
 
+// AllWithContext is like All, but stops when ctx is done and returns the occurrences
+// found so far with the context error, ex. to bound the expansion of a large set.
+func (set *Set) AllWithContext(ctx context.Context) ([]time.Time, error) {
+	return allWithContext(ctx, set.iteratorWithContext(ctx))
+}
+
 // Between returns all the occurrences of the rrule between after and before.
 // The inc keyword defines what happens if after and/or before are themselves occurrences.
 // With inc == True, they will be included in the list, if they are found in the recurrence set.
@@ -153,6 +432,19 @@ func (set *Set) Between(after, before time.Time, inc bool) []time.Time {
 	return between(set.Iterator(), after, before, inc)
 }
 
+// BetweenBounds is like Between but the inclusivity of after and before is set independently,
+// ex. incStart == true and incEnd == false gives the half-open window [after, before).
+func (set *Set) BetweenBounds(after, before time.Time, incStart, incEnd bool) []time.Time {
+	return betweenBounds(set.Iterator(), after, before, incStart, incEnd)
+}
+
+// CountBetween returns the number of occurrences of the set between after and before,
+// with the same inc semantics as Between, without building them into a slice.
+// Excluded dates are not counted.
+func (set *Set) CountBetween(after, before time.Time, inc bool) int {
+	return countBetween(set.Iterator(), after, before, inc)
+}
+
 // Before Returns the last recurrence before the given datetime instance,
 // or time.Time's zero value if no recurrence match.
 // The inc keyword defines what happens if dt is an occurrence.
@@ -168,3 +460,171 @@ func (set *Set) Before(dt time.Time, inc bool) time.Time {
 func (set *Set) After(dt time.Time, inc bool) time.Time {
 	return after(set.Iterator(), dt, inc)
 }
+
+// Difference returns the occurrences of the set between after and before (inclusive)
+// which are not occurrences of other. Occurrences are compared as instants,
+// regardless of their location.
+func (set *Set) Difference(other *Set, after, before time.Time) []time.Time {
+	result := []time.Time{}
+	exclude := other.Between(after, before, true)
+	for _, dt := range set.Between(after, before, true) {
+		for len(exclude) != 0 && exclude[0].Before(dt) {
+			exclude = exclude[1:]
+		}
+		if len(exclude) == 0 || !exclude[0].Equal(dt) {
+			result = append(result, dt)
+		}
+	}
+	return result
+}
+
+// Merge adds the rrules, rdates, exrules, exdates and XProps of other to the set,
+// the occurrences of the set are then the union of the occurrences of both sets.
+// Exclusions win within each set, but the union is across sets: a date excluded by other
+// is still an occurrence if it's included by the set, and vice versa.
+// So the rules and dates of a set with exclusions are kept apart with its exclusions,
+// they are not returned by GetRRule, GetRDate, etc., and exclusions added to the set
+// afterwards apply to all its occurrences.
+// Dates already in the set, compared as instants, and XProps lines already in the set
+// are not added twice.
+// The rules of other keep their DTSTART, the set loses its DTSTART, see SetDTStart,
+// if they don't all have it.
+func (set *Set) Merge(other *Set) {
+	if !other.rulesHaveDTStart(set.dtstart) {
+		set.dtstart = time.Time{}
+	}
+	if set.hasExclusions() {
+		// the exclusions of the set don't apply to other
+		set.merged = []*Set{set.member()}
+		set.rrule, set.rdate, set.rday, set.rperiod = nil, nil, nil, nil
+		set.exrule, set.exdate, set.exday = nil, nil, nil
+	}
+	if other.hasExclusions() || len(other.merged) != 0 {
+		set.merged = append(set.merged, other.member())
+	} else {
+		set.rrule = append(set.rrule, other.rrule...)
+		for _, dt := range other.rdate {
+			if !instantContains(set.rdate, dt) {
+				set.rdate = append(set.rdate, dt)
+			}
+		}
+		for _, day := range other.rday {
+			if !timeContains(set.rday, day) {
+				set.rday = append(set.rday, day)
+			}
+		}
+		for _, period := range other.rperiod {
+			if !periodContains(set.rperiod, period) {
+				set.rperiod = append(set.rperiod, period)
+			}
+		}
+	}
+	for _, line := range other.XProps {
+		if !stringContains(set.XProps, line) {
+			set.XProps = append(set.XProps, line)
+		}
+	}
+}
+
+// member returns a copy of the set to be merged into another one,
+// without DTSTART and XProps, which are the ones of the other set.
+func (set *Set) member() *Set {
+	result := set.Clone()
+	result.dtstart, result.XProps = time.Time{}, nil
+	return result
+}
+
+// hasExclusions reports whether the set has exrules or exdates.
+func (set *Set) hasExclusions() bool {
+	return len(set.exrule) != 0 || len(set.exdate) != 0 || len(set.exday) != 0
+}
+
+// rulesHaveDTStart reports whether all the rules of the set, and of the sets merged into it,
+// have the given DTSTART.
+func (set *Set) rulesHaveDTStart(dtstart time.Time) bool {
+	for _, r := range append(append([]*RRule(nil), set.rrule...), set.exrule...) {
+		if !r.dtstart.Equal(dtstart) {
+			return false
+		}
+	}
+	for _, member := range set.merged {
+		if !member.rulesHaveDTStart(dtstart) {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeSets returns a new Set combining the rrules, rdates, exrules, exdates and XProps
+// of all given sets, see Set.Merge for the semantics.
+func MergeSets(sets ...*Set) *Set {
+	result := Set{}
+	for _, set := range sets {
+		result.Merge(set)
+	}
+	return &result
+}
+
+// Clone returns a deep copy of the set, its rules and dates.
+func (set *Set) Clone() *Set {
+	result := Set{
+		dtstart: set.dtstart,
+		rdate:   append([]time.Time(nil), set.rdate...),
+		rday:    append([]time.Time(nil), set.rday...),
+		rperiod: append([]Occurrence(nil), set.rperiod...),
+		exdate:  append([]time.Time(nil), set.exdate...),
+		exday:   append([]time.Time(nil), set.exday...),
+		XProps:  append([]string(nil), set.XProps...),
+	}
+	for _, r := range set.rrule {
+		result.rrule = append(result.rrule, r.clone())
+	}
+	for _, r := range set.exrule {
+		result.exrule = append(result.exrule, r.clone())
+	}
+	for _, member := range set.merged {
+		result.merged = append(result.merged, member.Clone())
+	}
+	return &result
+}
+
+// Shift returns a copy of the set moved by d: DTSTART and UNTIL of each rule,
+// RDATEs, RDATE periods and EXDATEs are all offset by d, excluded days
+// move to the day of their midnight offset by d.
+// Shift moves absolute instants, it doesn't keep the wall clock across DST changes.
+// Occurrences given by BY* parts, ex. BYHOUR=9 or BYMONTHDAY=15, don't move,
+// so the occurrences shift uniformly only when they follow DTSTART.
+func (set *Set) Shift(d time.Duration) *Set {
+	result := Set{XProps: append([]string(nil), set.XProps...)}
+	if !set.dtstart.IsZero() {
+		result.dtstart = set.dtstart.Add(d)
+	}
+	for _, r := range set.rrule {
+		result.rrule = append(result.rrule, r.shift(d))
+	}
+	for _, dt := range set.rdate {
+		result.rdate = append(result.rdate, dt.Add(d))
+	}
+	for _, day := range set.rday {
+		// a day moved off midnight is a date-time
+		if shifted := day.Add(d); shifted.Equal(shifted.Truncate(24 * time.Hour)) {
+			result.rday = append(result.rday, shifted)
+		}
+	}
+	for _, period := range set.rperiod {
+		result.rperiod = append(result.rperiod, Occurrence{Start: period.Start.Add(d), End: period.End.Add(d)})
+	}
+	for _, r := range set.exrule {
+		result.exrule = append(result.exrule, r.shift(d))
+	}
+	for _, dt := range set.exdate {
+		result.exdate = append(result.exdate, dt.Add(d))
+	}
+	for _, day := range set.exday {
+		result.ExDateDay(day.Add(d))
+	}
+	for _, member := range set.merged {
+		result.merged = append(result.merged, member.Shift(d))
+	}
+	return &result
+}
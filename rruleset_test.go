@@ -1,6 +1,10 @@
 package rrule
 
 import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -271,3 +275,482 @@ func TestSetTrickyTimeZones(t *testing.T) {
 		t.Errorf("No all occurrences excluded by ExDate: [%+v]", occurrences)
 	}
 }
+
+func TestSetDifference(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 5,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+
+	newYork, _ := time.LoadLocation("America/New_York")
+	other := Set{}
+	r, _ = NewRRule(ROption{Freq: DAILY, Interval: 2, Count: 3,
+		Dtstart: time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC).In(newYork)})
+	other.RRule(r)
+	other.RDate(time.Date(1997, 9, 6, 9, 0, 0, 0, time.UTC))
+
+	value := set.Difference(&other,
+		time.Date(1997, 9, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 6, 9, 0, 0, 0, time.UTC))
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestMergeSets(t *testing.T) {
+	set1 := Set{}
+	r, _ := NewRRule(ROption{Freq: WEEKLY, Count: 2,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set1.RRule(r)
+	set1.RDate(time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC))
+
+	set2 := Set{}
+	r, _ = NewRRule(ROption{Freq: WEEKLY, Count: 2,
+		Dtstart: time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)})
+	set2.RRule(r)
+	set2.RDate(time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC))
+	set2.ExDate(time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC))
+	set1.XProps = []string{"X-WR-TIMEZONE:UTC"}
+	set2.XProps = []string{"X-WR-TIMEZONE:UTC", "X-WR-CALNAME:Meetings"}
+
+	set := MergeSets(&set1, &set2)
+	if rdates := set.GetRDate(); len(rdates) != 1 {
+		t.Errorf("get %v, want one rdate", rdates)
+	}
+	if value, want := strings.Join(set.XProps, "\n"), "X-WR-TIMEZONE:UTC\nX-WR-CALNAME:Meetings"; value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	// the EXDATE of set2 doesn't exclude the occurrence of set1
+	value := set.All()
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestMergeSetsExclusions(t *testing.T) {
+	a := Set{}
+	a.RDate(time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC))
+	a.RDate(time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC))
+	a.ExDate(time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC))
+	b := Set{}
+	b.RDate(time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC))
+	b.RDate(time.Date(2022, 1, 3, 9, 0, 0, 0, time.UTC))
+	b.ExDate(time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC))
+
+	set := MergeSets(&a, &b)
+	value := set.All()
+	want := []time.Time{time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 3, 9, 0, 0, 0, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := set.Clone().All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+
+	// an exclusion added afterwards applies to the merged set
+	set.ExDate(time.Date(2022, 1, 3, 9, 0, 0, 0, time.UTC))
+	if value := set.All(); !timesEqual(value, want[:2]) {
+		t.Errorf("get %v, want %v", value, want[:2])
+	}
+}
+
+func TestSetIsInfinite(t *testing.T) {
+	set := Set{}
+	set.RDate(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC))
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 3,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.ExRule(r)
+	if set.IsInfinite() {
+		t.Errorf("IsInfinite() = true, want false")
+	}
+	set.RRule(r)
+	if !set.IsInfinite() {
+		t.Errorf("IsInfinite() = false, want true")
+	}
+}
+
+func TestSetClone(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: YEARLY, Count: 1, Byweekday: []Weekday{TU},
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	set.RDate(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC))
+	want := set.String()
+
+	clone := set.Clone()
+	if value := clone.String(); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	clone.rrule[0].OrigOptions.Byweekday[0] = FR
+	clone.rdate[0] = time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC)
+	clone.ExDate(time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC))
+	if value := set.String(); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSetShift(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: DAILY, Interval: 2,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		Until:   time.Date(1997, 9, 12, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	r, _ = NewRRule(ROption{Freq: WEEKLY, Count: 3,
+		Dtstart: time.Date(1997, 9, 3, 18, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	set.RDate(time.Date(1997, 9, 5, 12, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 6, 9, 0, 0, 0, time.UTC))
+	want := set.All()
+	d := 49*time.Hour + 30*time.Minute
+	for i := range want {
+		want[i] = want[i].Add(d)
+	}
+	if value := set.Shift(d).All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := set.All(); len(value) != len(want) || !value[0].Equal(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Shift changed the set: %v", value)
+	}
+}
+
+func TestSetBetweenBounds(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 7,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	value := set.BetweenBounds(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC), true, false)
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSetWriteTo(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: YEARLY, Count: 1, Byweekday: []Weekday{TU},
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	set.ExRule(r)
+	set.RDate(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	n, err := set.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo returned error: %v", err)
+	}
+	want := set.String() + "\n"
+	if value := buf.String(); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("get %v bytes written, want %v", n, len(want))
+	}
+}
+
+func TestSetIteratorUnbounded(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	r, _ = NewRRule(ROption{Freq: WEEKLY,
+		Dtstart: time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)})
+	set.ExRule(r)
+	set.RDate(time.Date(1997, 9, 3, 18, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC))
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 18, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 6, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 7, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 9, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 11, 9, 0, 0, 0, time.UTC)}
+	next := set.Iterator()
+	value := []time.Time{}
+	for len(value) < len(want) {
+		dt, ok := next()
+		if !ok {
+			break
+		}
+		value = append(value, dt)
+	}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSetRecurrenceSortedDates(t *testing.T) {
+	set := Set{}
+	set.RDate(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC))
+	set.RDate(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC))
+	set.RDate(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC))
+	want := []string{"RDATE:19970902T090000Z", "RDATE:19970904T090000Z",
+		"EXDATE:19970901T090000Z", "EXDATE:19970903T090000Z"}
+	value := set.Recurrence()
+	if strings.Join(value, "\n") != strings.Join(want, "\n") {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if len(set.GetRDate()) != 3 {
+		t.Errorf("get %v, want the rdates left as they are", set.GetRDate())
+	}
+}
+
+func TestSetNormalize(t *testing.T) {
+	set := Set{}
+	set.RDate(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC))
+	set.RDate(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC))
+	set.RDate(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC))
+	set.Normalize()
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)}
+	if value := set.GetRDate(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	want = []time.Time{time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	if value := set.GetExDate(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSetDTStart(t *testing.T) {
+	set := Set{}
+	if value := set.DTStart(); !value.IsZero() {
+		t.Errorf("get %v, want zero time", value)
+	}
+	r, _ := NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	r, _ = NewRRule(ROption{Freq: DAILY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	want := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	if value := set.DTStart(); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSetGettersCopy(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 3,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	set.ExRule(r)
+	set.RDate(time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC))
+	set.GetRRule()[0] = nil
+	set.GetExRule()[0] = nil
+	set.GetRDate()[0] = time.Time{}
+	set.GetExDate()[0] = time.Time{}
+	if set.GetRRule()[0] != r || set.GetExRule()[0] != r {
+		t.Errorf("changing the returned rules changed the set")
+	}
+	if value := set.GetRDate(); value[0].IsZero() {
+		t.Errorf("changing the returned rdates changed the set: %v", value)
+	}
+	// drop the first exclusion
+	edited := Set{}
+	for _, r := range set.GetRRule() {
+		edited.RRule(r)
+	}
+	for _, dt := range set.GetExDate()[1:] {
+		edited.ExDate(dt)
+	}
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	if value := edited.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSetSetDTStart(t *testing.T) {
+	nyc, _ := time.LoadLocation("America/New_York")
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 3,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	set.SetDTStart(time.Date(1997, 9, 2, 9, 0, 0, 0, nyc))
+	r, _ = NewRRule(ROption{Freq: WEEKLY, Count: 2})
+	set.RRule(r)
+	r, _ = NewRRule(ROption{Freq: DAILY, Interval: 2})
+	set.ExRule(r)
+	set.RDate(time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC))
+	want := "DTSTART;TZID=America/New_York:19970902T090000\n" +
+		"RRULE:FREQ=DAILY;COUNT=3\n" +
+		"RRULE:FREQ=WEEKLY;COUNT=2\n" +
+		"RDATE:19970910T090000Z\n" +
+		"EXRULE:FREQ=DAILY;INTERVAL=2"
+	if value := strings.Join(set.Recurrence(), "\n"); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := set.DTStart(); !value.Equal(time.Date(1997, 9, 2, 9, 0, 0, 0, nyc)) {
+		t.Errorf("get %v, want the set DTSTART", value)
+	}
+	wantTimes := []time.Time{time.Date(1997, 9, 3, 9, 0, 0, 0, nyc),
+		time.Date(1997, 9, 9, 9, 0, 0, 0, nyc),
+		time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC)}
+	value := set.All()
+	if len(value) != len(wantTimes) {
+		t.Fatalf("get %v, want %v", value, wantTimes)
+	}
+	for i := range value {
+		if !value[i].Equal(wantTimes[i]) {
+			t.Errorf("get %v, want %v", value, wantTimes)
+		}
+	}
+	parsed, err := StrSliceToRRuleSet(set.Recurrence())
+	if err != nil {
+		t.Fatalf("StrSliceToRRuleSet returned error: %v", err)
+	}
+	if parsedValue := parsed.All(); len(parsedValue) != len(value) {
+		t.Errorf("get %v, want %v", parsedValue, value)
+	}
+}
+
+func TestSetSetDTStartAllDay(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: WEEKLY, Count: 2, AllDay: true})
+	set.RRule(r)
+	set.SetDTStart(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	want := "DTSTART;VALUE=DATE:20220101\nRRULE:FREQ=WEEKLY;COUNT=2"
+	if value := strings.Join(set.Recurrence(), "\n"); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	parsed, err := StrSliceToRRuleSet(set.Recurrence())
+	if err != nil {
+		t.Fatalf("StrSliceToRRuleSet returned error: %v", err)
+	}
+	if rules := parsed.GetRRule(); len(rules) != 1 || !rules[0].OrigOptions.AllDay {
+		t.Errorf("get %v, want an AllDay rule", rules)
+	}
+
+	// UNTIL stays the date of the DTSTART zone without the DTSTART of the rule
+	nyc, _ := time.LoadLocation("America/New_York")
+	set = Set{}
+	r, _ = NewRRule(ROption{Freq: WEEKLY, AllDay: true, Until: time.Date(2022, 2, 1, 4, 59, 59, 0, time.UTC)})
+	set.RRule(r)
+	set.SetDTStart(time.Date(2022, 1, 1, 0, 0, 0, 0, nyc))
+	want = "DTSTART;VALUE=DATE:20220101\nRRULE:FREQ=WEEKLY;UNTIL=20220131"
+	if value := strings.Join(set.Recurrence(), "\n"); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSetRDateDayRoundTrip(t *testing.T) {
+	lines := []string{
+		"RDATE;VALUE=DATE:20220101",
+		"RDATE:20220102T090000Z",
+		"EXDATE;VALUE=DATE:20220103",
+	}
+	set, err := StrSliceToRRuleSet(lines)
+	if err != nil {
+		t.Fatalf("StrSliceToRRuleSet returned error: %v", err)
+	}
+	want := strings.Join(lines, "\n")
+	if value := strings.Join(set.Recurrence(), "\n"); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := set.All(); len(value) != 2 || !value[0].Equal(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("get %v, want 2022-01-01 and 2022-01-02", value)
+	}
+	if value := set.Shift(3 * time.Hour).Recurrence()[0]; value != "RDATE:20220101T030000Z" {
+		t.Errorf("get %v, want %v", value, "RDATE:20220101T030000Z")
+	}
+	if value := set.Shift(24 * time.Hour).Recurrence()[0]; value != "RDATE;VALUE=DATE:20220102" {
+		t.Errorf("get %v, want %v", value, "RDATE;VALUE=DATE:20220102")
+	}
+}
+
+func TestSetConcurrentBetween(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: MONTHLY, Byweekday: []Weekday{MO.Nth(1), FR.Nth(-1)},
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	set.RDate(time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC))
+	set.RDate(time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 10, 6, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 26, 9, 0, 0, 0, time.UTC))
+	after, before := time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := set.Between(after, before, true)
+	wantRule := r.Between(after, before, true)
+
+	// run with -race to detect shared state modified by the iteration
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value := set.Between(after, before, true); !timesEqual(value, want) {
+				t.Errorf("get %v, want %v", value, want)
+			}
+			if value := r.Between(after, before, true); !timesEqual(value, wantRule) {
+				t.Errorf("get %v, want %v", value, wantRule)
+			}
+			r.After(after, false)
+			r.Before(before, false)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSetCountBetween(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 10,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	set.RDate(time.Date(1997, 9, 4, 12, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC))
+	set.ExDateDay(time.Date(1997, 9, 6, 0, 0, 0, 0, time.UTC))
+	after, before := time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC), time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC)
+	// 09-03, 09-04 twice, 09-07 and 09-08
+	if value := set.CountBetween(after, before, true); value != 5 {
+		t.Errorf("get %v, want 5", value)
+	}
+	if value := set.CountBetween(after, before, false); value != 3 {
+		t.Errorf("get %v, want 3", value)
+	}
+	if value, want := set.CountBetween(after, before, true), len(set.Between(after, before, true)); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSetAllWithContext(t *testing.T) {
+	set := Set{}
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 3,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	set.ExDate(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC))
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC)}
+	if value, err := set.AllWithContext(context.Background()); err != nil || !timesEqual(value, want) {
+		t.Errorf("get %v, %v, want %v", value, err, want)
+	}
+	r, _ = NewRRule(ROption{Freq: SECONDLY,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	set.RRule(r)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := set.AllWithContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("get %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,39 @@
+package rrule
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements driver.Valuer, the RRule is stored as its RFC string.
+// A nil RRule is stored as NULL. Runtime-only options, ex. ROption.DSTPolicy
+// and ROption.IncludeDTStart, are not stored.
+func (r *RRule) Value() (driver.Value, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner, it accepts a string or []byte
+// holding an RFC string. NULL scans to the zero RRule.
+func (r *RRule) Scan(src interface{}) error {
+	var str string
+	switch value := src.(type) {
+	case nil:
+		*r = RRule{}
+		return nil
+	case string:
+		str = value
+	case []byte:
+		str = string(value)
+	default:
+		return fmt.Errorf("cannot scan %T into RRule", src)
+	}
+	result, err := StrToRRule(str)
+	if err != nil {
+		return err
+	}
+	*r = *result
+	return nil
+}
@@ -0,0 +1,58 @@
+package rrule
+
+import (
+	"testing"
+)
+
+func TestSQLValue(t *testing.T) {
+	str := "FREQ=WEEKLY;DTSTART=20120201T093000Z;COUNT=3;BYDAY=MO,FR"
+	r, _ := StrToRRule(str)
+	value, err := r.Value()
+	if err != nil {
+		t.Fatalf("Value returned error: %v", err)
+	}
+	if value != str {
+		t.Errorf("get %v, want %v", value, str)
+	}
+	var empty *RRule
+	if value, err = empty.Value(); value != nil || err != nil {
+		t.Errorf("get %v, %v, want nil, nil", value, err)
+	}
+}
+
+func TestSQLScan(t *testing.T) {
+	str := "FREQ=WEEKLY;DTSTART=20120201T093000Z;COUNT=3;BYDAY=MO,FR"
+	want, _ := StrToRRule(str)
+	for _, src := range []interface{}{str, []byte(str)} {
+		r := RRule{}
+		if err := r.Scan(src); err != nil {
+			t.Fatalf("Scan(%v) returned error: %v", src, err)
+		}
+		if !timesEqual(r.All(), want.All()) {
+			t.Errorf("get %v, want %v", r.All(), want.All())
+		}
+		if r.String() != str {
+			t.Errorf("get %v, want %v", r.String(), str)
+		}
+	}
+}
+
+func TestSQLScanNull(t *testing.T) {
+	r, _ := StrToRRule("FREQ=DAILY;COUNT=3")
+	if err := r.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) returned error: %v", err)
+	}
+	if value := r.String(); value != "FREQ=YEARLY" {
+		t.Errorf("get %v, want %v", value, "FREQ=YEARLY")
+	}
+}
+
+func TestSQLScanInvalid(t *testing.T) {
+	r := RRule{}
+	if err := r.Scan(42); err == nil {
+		t.Errorf("Scan(42) = nil, want error")
+	}
+	if err := r.Scan("FREQ=HELLO"); err == nil {
+		t.Errorf("Scan(\"FREQ=HELLO\") = nil, want error")
+	}
+}
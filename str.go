@@ -17,6 +17,38 @@ const (
 	DateFormat = "20060102"
 )
 
+// Property is the name of an iCalendar property of a recurrence set, ex. RRULE.
+type Property string
+
+// Properties of a recurrence set
+const (
+	PropDTStart Property = "DTSTART"
+	PropRRule   Property = "RRULE"
+	PropExRule  Property = "EXRULE"
+	PropRDate   Property = "RDATE"
+	PropExDate  Property = "EXDATE"
+)
+
+// ParseError is returned by StrToROptionInLocation and the set parsers when the rfc string
+// can not be parsed. Property and Value hold the failing property and its value, if any.
+type ParseError struct {
+	Property string
+	Value    string
+	// Line is the 1-based number of the failing line in the input of StrSliceToRRuleSet,
+	// StrToRRuleSet or ParseVEVENT, folded lines counting as in the input, or 0
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 func timeToStr(time time.Time) string {
 	return time.UTC().Format(DateTimeFormat)
 }
@@ -36,6 +68,48 @@ func strToTimeInLoc(str string, loc *time.Location) (time.Time, error) {
 	return time.Parse(DateTimeFormat, str)
 }
 
+// strToDtStart parses the value of a DTSTART property, with or without
+// parameters, ex. "20220101T090000Z", "TZID=America/New_York:20220101T090000"
+// or "VALUE=DATE:20220101", and tells whether it's a date.
+// A local date-time or a date is interpreted in the TZID zone if given, else in loc.
+func strToDtStart(str string, loc *time.Location) (time.Time, bool, error) {
+	tmp := strings.Split(str, ":")
+	if len(tmp) > 2 {
+		return time.Time{}, false, errors.New("bad format")
+	}
+	valueType := ""
+	if len(tmp) == 2 {
+		for _, param := range strings.Split(tmp[0], ";") {
+			keyValue := strings.SplitN(param, "=", 2)
+			if len(keyValue) != 2 {
+				return time.Time{}, false, fmt.Errorf("bad DTSTART parm: %v", param)
+			}
+			switch key, value := strings.ToUpper(keyValue[0]), keyValue[1]; key {
+			case "TZID":
+				tz, err := time.LoadLocation(value)
+				if err != nil {
+					return time.Time{}, false, fmt.Errorf("unknown TZID %q: %v", value, err)
+				}
+				loc = tz
+			case "VALUE":
+				valueType = strings.ToUpper(value)
+				if valueType != "DATE-TIME" && valueType != "DATE" {
+					return time.Time{}, false, fmt.Errorf("unsupported DTSTART parm: %v", param)
+				}
+			default:
+				return time.Time{}, false, fmt.Errorf("unsupported DTSTART parm: %v", param)
+			}
+		}
+		tmp = tmp[1:]
+	}
+	isDate := len(tmp[0]) == len(DateFormat) && valueType != "DATE-TIME"
+	if valueType == "DATE" && !isDate {
+		return time.Time{}, false, fmt.Errorf("DTSTART %s is not a DATE", tmp[0])
+	}
+	t, err := strToTimeInLoc(tmp[0], loc)
+	return t, isDate, err
+}
+
 func (f Frequency) String() string {
 	return [...]string{
 		"YEARLY", "MONTHLY", "WEEKLY", "DAILY",
@@ -54,6 +128,45 @@ func strToFreq(str string) (Frequency, error) {
 	return result, nil
 }
 
+// ParseFrequency parses a frequency in its RFC form, ex. "WEEKLY",
+// regardless of case and surrounding spaces, ex. " weekly".
+func ParseFrequency(s string) (Frequency, error) {
+	return strToFreq(strings.ToUpper(strings.TrimSpace(s)))
+}
+
+// MarshalText implements encoding.TextMarshaler, the frequency is encoded in its RFC form.
+func (f Frequency) MarshalText() ([]byte, error) {
+	if f < YEARLY || f > SECONDLY {
+		return nil, fmt.Errorf("undefined frequency: %d", int(f))
+	}
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *Frequency) UnmarshalText(text []byte) error {
+	result, err := strToFreq(string(text))
+	if err != nil {
+		return err
+	}
+	*f = result
+	return nil
+}
+
+func (skip Skip) String() string {
+	return [...]string{"OMIT", "BACKWARD", "FORWARD"}[skip]
+}
+
+func strToSkip(str string) (Skip, error) {
+	skipMap := map[string]Skip{
+		"OMIT": SkipOmit, "BACKWARD": SkipBackward, "FORWARD": SkipForward,
+	}
+	result, ok := skipMap[str]
+	if !ok {
+		return 0, errors.New("undefined skip: " + str)
+	}
+	return result, nil
+}
+
 func (wday Weekday) String() string {
 	s := [...]string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}[wday.weekday]
 	if wday.n == 0 {
@@ -78,11 +191,19 @@ func strToWeekday(str string) (Weekday, error) {
 		if e != nil {
 			return Weekday{}, e
 		}
+		if n == 0 {
+			return Weekday{}, errors.New("invalid weekday ordinal: " + str)
+		}
 		result.n = n
 	}
 	return result, nil
 }
 
+// ParseWeekday parses a weekday in its RFC form, ex. "MO", "+2MO" or "-1FR".
+func ParseWeekday(s string) (Weekday, error) {
+	return strToWeekday(s)
+}
+
 func strToWeekdays(value string) ([]Weekday, error) {
 	contents := strings.Split(value, ",")
 	result := make([]Weekday, len(contents))
@@ -120,21 +241,102 @@ func strToInts(value string) ([]int, error) {
 	return result, nil
 }
 
+// tzidOf returns the TZID of a time in a named time zone other than UTC,
+// or "" if the time is in UTC, the local or a fixed zone.
+func tzidOf(t time.Time) string {
+	name := t.Location().String()
+	if name == "UTC" || name == "Local" {
+		return ""
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// rfcLines returns the lines of the option as an iCalendar property with the given name,
+// preceded by a DTSTART line if DTSTART is in a named time zone or a date, see dtstartLine.
+func (option *ROption) rfcLines(name Property) []string {
+	result := []string{}
+	if line := option.dtstartLine(); line != "" {
+		result = append(result, line)
+	}
+	return append(result, fmt.Sprintf("%s:%s", name, option.rruleString(nil, true)))
+}
+
+// dtstartLine returns the DTSTART line of the option if DTSTART can't be part of the RRULE value:
+// a DTSTART;VALUE=DATE line if the option is AllDay, a DTSTART;TZID line if DTSTART is
+// in a named time zone, else "".
+func (option *ROption) dtstartLine() string {
+	if option.Dtstart.IsZero() {
+		return ""
+	}
+	if option.AllDay {
+		return fmt.Sprintf("%s;VALUE=DATE:%s", PropDTStart, option.Dtstart.Format(DateFormat))
+	}
+	if tzid := tzidOf(option.Dtstart); tzid != "" {
+		return fmt.Sprintf("%s;TZID=%s:%s", PropDTStart, tzid, option.Dtstart.Format(LocalDateTimeFormat))
+	}
+	return ""
+}
+
+// String returns the RRULE string of the option, ex. "FREQ=DAILY;DTSTART=20220101T080000Z".
+// If DTSTART is in a named time zone other than UTC it keeps its zone,
+// ex. "DTSTART;TZID=Europe/Paris:20220101T090000\nRRULE:FREQ=DAILY",
+// and if the option is AllDay it's a date, ex. "DTSTART;VALUE=DATE:20220101\nRRULE:FREQ=WEEKLY".
 func (option *ROption) String() string {
+	if option.dtstartLine() != "" {
+		return strings.Join(option.rfcLines(PropRRule), "\n")
+	}
+	return option.rruleString(nil, true)
+}
+
+// SerializeOptions controls how ROption.StringWithOptions writes an option.
+type SerializeOptions struct {
+	// OmitDefaults omits INTERVAL=1 and WKST=MO,
+	// otherwise they are always written, even when not set
+	OmitDefaults bool
+}
+
+// StringWithOptions is like String, with control over the parts having default values.
+// String writes INTERVAL only when it's set, and WKST only when it's not MO or was given explicitly.
+func (option *ROption) StringWithOptions(opts SerializeOptions) string {
+	line := option.rruleString(&opts, true)
+	if dtstart := option.dtstartLine(); dtstart != "" {
+		return fmt.Sprintf("%s\n%s:%s", dtstart, PropRRule, line)
+	}
+	return line
+}
+
+// rruleString returns the RRULE value of the option, including DTSTART if withDTStart
+// and it doesn't have its own line, see dtstartLine.
+// UNTIL is a date if the option is AllDay, else a UTC date-time.
+func (option *ROption) rruleString(opts *SerializeOptions, withDTStart bool) string {
 	result := []string{fmt.Sprintf("FREQ=%v", option.Freq)}
-	if !option.Dtstart.IsZero() {
+	if withDTStart && !option.Dtstart.IsZero() && option.dtstartLine() == "" {
 		result = append(result, fmt.Sprintf("DTSTART=%s", timeToStr(option.Dtstart)))
 	}
-	if option.Interval != 0 {
-		result = append(result, fmt.Sprintf("INTERVAL=%v", option.Interval))
+	interval := option.Interval
+	if interval == 0 && opts != nil && !opts.OmitDefaults {
+		interval = 1
 	}
-	if option.Wkst != MO {
+	if interval > 1 || interval == 1 && (opts == nil || !opts.OmitDefaults) {
+		result = append(result, fmt.Sprintf("INTERVAL=%v", interval))
+	}
+	if option.Wkst != MO || opts == nil && option.wkstSet || opts != nil && !opts.OmitDefaults {
 		result = append(result, fmt.Sprintf("WKST=%v", option.Wkst))
 	}
 	if option.Count != 0 {
 		result = append(result, fmt.Sprintf("COUNT=%v", option.Count))
 	}
-	if !option.Until.IsZero() {
+	if option.AllDay && !option.Until.IsZero() {
+		// the date of UNTIL in the DTSTART zone, as a date-only UNTIL is parsed
+		until := option.Until
+		if !option.Dtstart.IsZero() {
+			until = until.In(option.Dtstart.Location())
+		}
+		result = append(result, fmt.Sprintf("UNTIL=%s", until.Format(DateFormat)))
+	} else if !option.Until.IsZero() {
 		result = append(result, fmt.Sprintf("UNTIL=%v", timeToStr(option.Until)))
 	}
 	result = appendIntsOption(result, "BYSETPOS", option.Bysetpos)
@@ -153,6 +355,9 @@ func (option *ROption) String() string {
 	result = appendIntsOption(result, "BYMINUTE", option.Byminute)
 	result = appendIntsOption(result, "BYSECOND", option.Bysecond)
 	result = appendIntsOption(result, "BYEASTER", option.Byeaster)
+	if option.Skip != SkipOmit {
+		result = append(result, fmt.Sprintf("SKIP=%v", option.Skip))
+	}
 	return strings.Join(result, ";")
 }
 
@@ -163,36 +368,81 @@ func StrToROption(rfcString string) (*ROption, error) {
 
 // StrToROptionInLocation is same as StrToROption but in case local
 // time is supplied as date-time/date field (ex. UNTIL), it is parsed
-// as a time in a given location (time zone)
+// as a time in a given location (time zone).
+// The rule may be preceded by a DTSTART line carrying a TZID parameter,
+// ex. "DTSTART;TZID=America/New_York:20220101T090000\nRRULE:FREQ=DAILY",
+// in which case local times are parsed in the TZID zone.
+// A date DTSTART, ex. "DTSTART;VALUE=DATE:20220101" or "DTSTART=20220101", makes the option AllDay.
+// A date-only UNTIL, ex. "UNTIL=20221231", means the end of that day in the DTSTART zone,
+// so that occurrences on that day are included whatever their time.
+// Property names and values, ex. "freq=daily", are case-insensitive.
+// Folded lines are unfolded, and empty parts left by a trailing or doubled ";" are skipped.
 func StrToROptionInLocation(rfcString string, loc *time.Location) (*ROption, error) {
-	rfcString = strings.TrimSpace(rfcString)
+	rfcString = strings.TrimSpace(unfoldLines(rfcString))
 	if len(rfcString) == 0 {
-		return nil, errors.New("empty string")
+		return nil, &ParseError{Err: errors.New("empty string")}
 	}
 	result := ROption{}
+	if lines := strings.Split(rfcString, "\n"); len(lines) == 2 {
+		dtstartStr := strings.TrimSpace(lines[0])
+		nameLen := strings.IndexAny(dtstartStr, ";:")
+		if nameLen < 0 || Property(strings.ToUpper(dtstartStr[:nameLen])) != PropDTStart {
+			return nil, &ParseError{Value: dtstartStr, Err: errors.New("expect DTSTART line before RRULE")}
+		}
+		var e error
+		result.Dtstart, result.AllDay, e = strToDtStart(dtstartStr[nameLen+1:], loc)
+		if e != nil {
+			return nil, &ParseError{Property: string(PropDTStart), Value: dtstartStr[nameLen+1:], Err: e}
+		}
+		loc = result.Dtstart.Location()
+		rfcString = strings.TrimSpace(lines[1])
+	} else if len(lines) > 2 {
+		return nil, &ParseError{Value: rfcString, Err: errors.New("wrong format")}
+	}
+	if prefix := string(PropRRule) + ":"; strings.HasPrefix(strings.ToUpper(rfcString), prefix) {
+		rfcString = rfcString[len(prefix):]
+	}
+	if strings.Trim(rfcString, "; \t") == "" {
+		return nil, &ParseError{Value: rfcString, Err: errors.New("wrong format")}
+	}
+	untilIsDate := false
 	for _, attr := range strings.Split(rfcString, ";") {
+		if strings.TrimSpace(attr) == "" {
+			continue
+		}
 		keyValue := strings.Split(attr, "=")
 		if len(keyValue) != 2 {
-			return nil, errors.New("wrong format")
+			return nil, &ParseError{Value: attr, Err: errors.New("wrong format")}
+		}
+		// some producers put spaces around ";" and "=", or use lowercase
+		key := strings.ToUpper(strings.TrimSpace(keyValue[0]))
+		value := strings.TrimSpace(keyValue[1])
+		if len(key) == 0 {
+			return nil, &ParseError{Value: attr, Err: errors.New("option has no name")}
 		}
-		key, value := keyValue[0], keyValue[1]
 		if len(value) == 0 {
-			return nil, errors.New(key + " option has no value")
+			return nil, &ParseError{Property: key, Err: errors.New(key + " option has no value")}
 		}
 		var e error
 		switch key {
 		case "FREQ":
-			result.Freq, e = strToFreq(value)
+			result.Freq, e = strToFreq(strings.ToUpper(value))
 		case "DTSTART":
-			result.Dtstart, e = strToTimeInLoc(value, loc)
+			result.Dtstart, e = strToTimeInLoc(strings.ToUpper(value), loc)
+			result.AllDay = len(value) == len(DateFormat)
 		case "INTERVAL":
 			result.Interval, e = strconv.Atoi(value)
+			if e == nil && result.Interval <= 0 {
+				e = errors.New("INTERVAL must be positive")
+			}
 		case "WKST":
-			result.Wkst, e = strToWeekday(value)
+			result.Wkst, e = strToWeekday(strings.ToUpper(value))
+			result.wkstSet = e == nil
 		case "COUNT":
 			result.Count, e = strconv.Atoi(value)
 		case "UNTIL":
-			result.Until, e = strToTimeInLoc(value, loc)
+			result.Until, e = strToTimeInLoc(strings.ToUpper(value), loc)
+			untilIsDate = len(value) == len(DateFormat)
 		case "BYSETPOS":
 			result.Bysetpos, e = strToInts(value)
 		case "BYMONTH":
@@ -204,7 +454,7 @@ func StrToROptionInLocation(rfcString string, loc *time.Location) (*ROption, err
 		case "BYWEEKNO":
 			result.Byweekno, e = strToInts(value)
 		case "BYDAY":
-			result.Byweekday, e = strToWeekdays(value)
+			result.Byweekday, e = strToWeekdays(strings.ToUpper(value))
 		case "BYHOUR":
 			result.Byhour, e = strToInts(value)
 		case "BYMINUTE":
@@ -213,12 +463,27 @@ func StrToROptionInLocation(rfcString string, loc *time.Location) (*ROption, err
 			result.Bysecond, e = strToInts(value)
 		case "BYEASTER":
 			result.Byeaster, e = strToInts(value)
+		case "SKIP":
+			result.Skip, e = strToSkip(strings.ToUpper(value))
 		default:
-			return nil, errors.New("unknown RRULE property: " + key)
+			e = errors.New("unknown RRULE property: " + key)
+		}
+		if numErr := (*strconv.NumError)(nil); errors.As(e, &numErr) {
+			// strconv errors name the bad token but not the property
+			e = fmt.Errorf("%s: %w", key, e)
 		}
 		if e != nil {
-			return nil, e
+			return nil, &ParseError{Property: key, Value: value, Err: e}
+		}
+	}
+	if untilIsDate {
+		// a date-only UNTIL includes the whole day, in the DTSTART zone
+		zone := loc
+		if !result.Dtstart.IsZero() {
+			zone = result.Dtstart.Location()
 		}
+		year, month, day := result.Until.Date()
+		result.Until = time.Date(year, month, day+1, 0, 0, 0, 0, zone).Add(-time.Nanosecond)
 	}
 	return &result, nil
 }
@@ -227,98 +492,414 @@ func (r *RRule) String() string {
 	return r.OrigOptions.String()
 }
 
+// RFCString returns the RRule as an iCalendar property, ex. "RRULE:FREQ=DAILY;DTSTART=20220101T080000Z",
+// preceded by a DTSTART;TZID line if DTSTART is in a named time zone, as String does.
+func (r *RRule) RFCString() string {
+	return strings.Join(r.OrigOptions.rfcLines(PropRRule), "\n")
+}
+
 func (set *Set) String() string {
 	res := set.Recurrence()
 	return strings.Join(res, "\n")
 }
 
-// StrToRRule converts string to RRule
+// StrToRRule converts string to RRule, the BY* parts of the parsed option are validated
+// as in ROption.Validate. Since real feeds sometimes have both COUNT and UNTIL,
+// they are accepted together, see NewRRule.
 func StrToRRule(rfcString string) (*RRule, error) {
 	option, e := StrToROption(rfcString)
 	if e != nil {
 		return nil, e
 	}
+	if e = option.validateParts(); e != nil {
+		return nil, e
+	}
 	return NewRRule(*option)
 }
 
-// StrToRRuleSet converts string to RRuleSet
+// StrToRRuleWithStart is like StrToRRule, for rules stored apart from their DTSTART:
+// dtstart is the DTSTART of the rule, and local times of the string are in its zone.
+// The string may still have a DTSTART, it's an error if it's not the same instant as dtstart.
+func StrToRRuleWithStart(rfcString string, dtstart time.Time) (*RRule, error) {
+	option, e := StrToROptionInLocation(rfcString, dtstart.Location())
+	if e != nil {
+		return nil, e
+	}
+	if e = option.validateParts(); e != nil {
+		return nil, e
+	}
+	if !option.Dtstart.IsZero() && !option.Dtstart.Equal(dtstart.Truncate(time.Second)) {
+		return nil, fmt.Errorf("DTSTART %s conflicts with %s", timeToStr(option.Dtstart), timeToStr(dtstart))
+	}
+	option.Dtstart = dtstart
+	return NewRRule(*option)
+}
+
+// unfoldLines removes RFC 5545 line folding, a CRLF followed by a space or a tab.
+// A bare LF is accepted in place of CRLF.
+func unfoldLines(s string) string {
+	return strings.NewReplacer("\r\n ", "", "\r\n\t", "", "\n ", "", "\n\t", "").Replace(s)
+}
+
+// unfoldNumbered removes RFC 5545 line folding from lines, as unfoldLines does,
+// and returns the 1-based number in lines of the first line of each unfolded line.
+func unfoldNumbered(lines []string) ([]string, []int) {
+	var unfolded []string
+	var numbers []int
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r\n")
+		if len(unfolded) != 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+		numbers = append(numbers, i+1)
+	}
+	return unfolded, numbers
+}
+
+// StrToRRuleSet converts string to RRuleSet.
+// Folded lines are unfolded and lines may end with LF or CRLF.
 func StrToRRuleSet(s string) (*Set, error) {
-	s = strings.TrimSpace(s)
-	if s == "" {
+	if strings.TrimSpace(unfoldLines(s)) == "" {
 		return nil, errors.New("empty string")
 	}
-	ss := strings.Split(s, "\n")
-	return StrSliceToRRuleSet(ss)
+	return strSliceToRRuleSet(unfoldNumbered(strings.Split(s, "\n")))
 }
 
-// StrSliceToRRuleSet converts given str slice to RRuleSet
+// StrSliceToRRuleSet converts given str slice to RRuleSet.
+// Vendor extension lines, named X-*, are kept in Set.XProps.
+// A DTSTART line, ex. "DTSTART;TZID=America/New_York:20220101T090000",
+// applies to the RRULE/EXRULE lines following it which have no DTSTART of their own,
+// as AllDay rules if it's a date, ex. "DTSTART;VALUE=DATE:20220101".
+// It's the DTSTART of the set, see Set.SetDTStart, unless rules with another DTSTART come before it.
+// Errors are *ParseError, with the number of the failing line.
 func StrSliceToRRuleSet(ss []string) (*Set, error) {
+	return strSliceToRRuleSet(ss, nil)
+}
+
+// strSliceToRRuleSet is StrSliceToRRuleSet, numbers holding the line number of each line
+// for errors, ss being numbered from 1 if nil.
+func strSliceToRRuleSet(ss []string, numbers []int) (*Set, error) {
 	set := Set{}
-	for _, line := range ss {
-		line = strings.ToUpper(strings.TrimSpace(line))
+	dtstart, allDay := time.Time{}, false
+	for i, line := range ss {
+		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
+		number := i + 1
+		if numbers != nil {
+			number = numbers[i]
+		}
 		nameLen := strings.IndexAny(line, ";:")
 		if nameLen < 0 {
-			return nil, errors.New("bad format")
+			return nil, &ParseError{Value: line, Line: number, Err: errors.New("bad format")}
+		}
+		name := Property(strings.ToUpper(line[:nameLen]))
+		lineError := func(err error) error {
+			return &ParseError{Property: string(name), Value: line[nameLen+1:], Line: number, Err: err}
+		}
+		if strings.HasPrefix(string(name), "X-") {
+			// keep vendor extensions verbatim
+			set.XProps = append(set.XProps, line)
+			continue
+		}
+		if name != PropDTStart && name != PropRDate && name != PropExDate {
+			// TZID values are case sensitive, keep DTSTART, RDATE and EXDATE as they are
+			line = strings.ToUpper(line)
 		}
-		name := line[:nameLen]
 
 		switch name {
-		case "RRULE", "EXRULE":
-			r, err := StrToRRule(line[nameLen+1:])
+		case PropDTStart:
+			var err error
+			dtstart, allDay, err = strToDtStart(line[nameLen+1:], time.UTC)
 			if err != nil {
-				return nil, fmt.Errorf("strToRRule failed: %v", err)
+				return nil, lineError(fmt.Errorf("strToDtStart failed: %w", err))
+			}
+			if len(set.rrule) == 0 && len(set.exrule) == 0 {
+				// the DTSTART of the set, shared by its rules
+				set.SetDTStart(dtstart)
+			} else if !set.dtstart.Equal(dtstart) {
+				// the rules keep their own DTSTART
+				set.dtstart = time.Time{}
+			}
+		case PropRRule, PropExRule:
+			loc := time.UTC
+			if !dtstart.IsZero() {
+				loc = dtstart.Location()
+			}
+			option, err := StrToROptionInLocation(line[nameLen+1:], loc)
+			if pe, ok := err.(*ParseError); ok {
+				// already a ParseError of the failing part
+				pe.Line = number
+				return nil, pe
+			}
+			if err == nil {
+				err = option.validateParts()
 			}
-			if name == "RRULE" {
+			if err != nil {
+				return nil, lineError(fmt.Errorf("strToRRule failed: %w", err))
+			}
+			if option.Dtstart.IsZero() {
+				option.Dtstart, option.AllDay = dtstart, allDay
+			} else if !option.Dtstart.Equal(set.dtstart) {
+				set.dtstart = time.Time{}
+			}
+			r, err := NewRRule(*option)
+			if err != nil {
+				return nil, lineError(fmt.Errorf("strToRRule failed: %w", err))
+			}
+			if name == PropRRule {
 				set.RRule(r)
 			} else {
 				set.ExRule(r)
 			}
-		case "RDATE", "EXDATE":
-			ts, err := StrToDates(line[nameLen+1:])
+		case PropRDate, PropExDate:
+			params := line[nameLen:]
+			if i := strings.Index(params, ":"); i >= 0 {
+				params = params[:i]
+			}
+			if name == PropRDate && strings.Contains(strings.ToUpper(params), "VALUE=PERIOD") {
+				periods, err := StrToPeriods(line[nameLen+1:])
+				if err != nil {
+					return nil, lineError(fmt.Errorf("strToPeriods failed: %w", err))
+				}
+				for _, p := range periods {
+					set.RPeriod(p)
+				}
+				continue
+			}
+			ts, valueType, err := strToDates(line[nameLen+1:])
 			if err != nil {
-				return nil, fmt.Errorf("strToDates failed: %v", err)
+				return nil, lineError(fmt.Errorf("strToDates failed: %w", err))
 			}
 			for _, t := range ts {
-				if name == "RDATE" {
+				switch {
+				case name == PropRDate && valueType == "DATE":
+					set.RDateDay(t)
+				case name == PropRDate:
 					set.RDate(t)
-				} else {
+				case valueType == "DATE":
+					// an EXDATE of DATE type excludes the whole day
+					set.ExDateDay(t)
+				default:
 					set.ExDate(t)
 				}
 			}
 		default:
-			return nil, fmt.Errorf("unsupported property: %v", name)
+			return nil, lineError(fmt.Errorf("unsupported property: %v", name))
 		}
 	}
 
 	return &set, nil
 }
 
-// StrToDates accepts string with format: "VALUE=DATE-TIME:{time},{time},...,{time}"
+// ParseVEVENT extracts the recurrence set of a VEVENT block, given as lines
+// including or not its BEGIN:VEVENT and END:VEVENT wrapper.
+// With the wrapper, only the first VEVENT is read, so the lines of a whole
+// VCALENDAR may be given, and its VTIMEZONE rules are ignored.
+// DTSTART applies to the rules wherever it appears in the block,
+// other properties, and nested components such as VALARM, are ignored.
+func ParseVEVENT(lines []string) (*Set, error) {
+	unfolded, numbers := unfoldNumbered(lines)
+
+	inEvent := true
+	for _, line := range unfolded {
+		if strings.EqualFold(strings.TrimSpace(line), "BEGIN:VEVENT") {
+			inEvent = false
+			break
+		}
+	}
+	var dtstart, recurrence []string
+	var dtstartNumber, recurrenceNumbers []int
+	// depth counts the components nested in the VEVENT
+	depth := 0
+loop:
+	for i, line := range unfolded {
+		line = strings.TrimSpace(line)
+		nameLen := strings.IndexAny(line, ";:")
+		if nameLen < 0 {
+			continue
+		}
+		name := Property(strings.ToUpper(line[:nameLen]))
+		switch {
+		case name == "BEGIN":
+			if !inEvent {
+				inEvent = strings.EqualFold(line[nameLen+1:], "VEVENT")
+			} else {
+				depth++
+			}
+		case name == "END" && inEvent:
+			if depth == 0 && strings.EqualFold(line[nameLen+1:], "VEVENT") {
+				break loop
+			}
+			depth--
+		case !inEvent || depth != 0:
+			// outside the VEVENT or inside a nested component
+		case name == PropDTStart:
+			dtstart, dtstartNumber = []string{line}, []int{numbers[i]}
+		case name == PropRRule || name == PropExRule || name == PropRDate || name == PropExDate:
+			recurrence = append(recurrence, line)
+			recurrenceNumbers = append(recurrenceNumbers, numbers[i])
+		}
+	}
+	return strSliceToRRuleSet(append(dtstart, recurrence...), append(dtstartNumber, recurrenceNumbers...))
+}
+
+// StrToDates accepts string with format: "VALUE=DATE-TIME:{time},{time},...,{time}",
+// "VALUE=DATE:{date},{date},...,{date}"
 // or simply "{time},{time},...{time}" and parses it to array of dates
-// may be used to parse RDATE/EXDATE rules
+// may be used to parse RDATE/EXDATE rules.
+// Dates (VALUE=DATE) are returned as midnight times.
+// Date and date-time values can not be mixed in one property.
+// A TZID parameter, ex. "TZID=America/Chicago:20220101T090000", gives the zone of local times,
+// which are UTC otherwise.
 func StrToDates(str string) (ts []time.Time, err error) {
+	ts, _, err = strToDates(str)
+	return
+}
+
+// strToDates is StrToDates, also returning the value type of the dates, DATE or DATE-TIME.
+func strToDates(str string) (ts []time.Time, valueType string, err error) {
 	tmp := strings.Split(str, ":")
 	if len(tmp) > 2 {
-		return nil, fmt.Errorf("bad format")
+		return nil, "", fmt.Errorf("bad format")
 	}
+	loc := time.UTC
 	if len(tmp) == 2 {
 		params := strings.Split(tmp[0], ";")
 		for _, param := range params {
-			if param != "VALUE=DATE-TIME" {
-				return nil, fmt.Errorf("unsupported RDATE/EXDATE parm: %v", param)
+			switch upper := strings.ToUpper(param); {
+			case upper == "VALUE=DATE-TIME":
+				valueType = "DATE-TIME"
+			case upper == "VALUE=DATE":
+				valueType = "DATE"
+			case strings.HasPrefix(upper, "TZID="):
+				tz, err := time.LoadLocation(param[len("TZID="):])
+				if err != nil {
+					return nil, "", fmt.Errorf("unknown TZID %q: %v", param[len("TZID="):], err)
+				}
+				loc = tz
+			default:
+				return nil, "", fmt.Errorf("unsupported RDATE/EXDATE parm: %v", param)
 			}
 		}
 		tmp = tmp[1:]
 	}
 	for _, datestr := range strings.Split(tmp[0], ",") {
-		t, err := strToTime(datestr)
+		dateType := "DATE-TIME"
+		if len(datestr) == len(DateFormat) {
+			dateType = "DATE"
+		}
+		if valueType == "" {
+			// without VALUE parameter the first value decides the type
+			valueType = dateType
+		} else if valueType != dateType {
+			return nil, "", fmt.Errorf("%v value %q in %v property", dateType, datestr, valueType)
+		}
+		t, err := strToTimeInLoc(strings.ToUpper(datestr), loc)
 		if err != nil {
-			return nil, fmt.Errorf("strToTime failed: %v", err)
+			return nil, "", fmt.Errorf("strToTime failed: %v", err)
 		}
 		ts = append(ts, t)
 	}
 	return
 }
+
+// StrToPeriods parses the value of an RDATE property of PERIOD value type, each period
+// having an explicit end or a duration, ex.
+// "VALUE=PERIOD:19970101T180000Z/19970102T070000Z,19970109T180000Z/PT5H30M".
+// Periods can not be mixed with dates or date-times in one property.
+func StrToPeriods(str string) ([]Occurrence, error) {
+	tmp := strings.Split(str, ":")
+	if len(tmp) > 2 {
+		return nil, fmt.Errorf("bad format")
+	}
+	if len(tmp) == 2 {
+		for _, param := range strings.Split(tmp[0], ";") {
+			if strings.ToUpper(param) != "VALUE=PERIOD" {
+				return nil, fmt.Errorf("unsupported RDATE parm: %v", param)
+			}
+		}
+		tmp = tmp[1:]
+	}
+	result := []Occurrence{}
+	for _, periodStr := range strings.Split(strings.ToUpper(tmp[0]), ",") {
+		bounds := strings.Split(periodStr, "/")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("value %q in PERIOD property", periodStr)
+		}
+		start, err := strToTime(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("strToTime failed: %v", err)
+		}
+		var end time.Time
+		if strings.HasPrefix(bounds[1], "P") || strings.HasPrefix(bounds[1], "+P") {
+			days, d, err := strToDuration(bounds[1])
+			if err != nil {
+				return nil, err
+			}
+			end = start.AddDate(0, 0, days).Add(d)
+		} else if end, err = strToTime(bounds[1]); err != nil {
+			return nil, fmt.Errorf("strToTime failed: %v", err)
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("period %q does not end after its start", periodStr)
+		}
+		result = append(result, Occurrence{start, end})
+	}
+	return result, nil
+}
+
+// strToDuration parses a positive RFC 5545 duration, ex. "P1W", "P1DT2H" or "PT30M",
+// into its nominal days and its exact time part.
+func strToDuration(str string) (days int, d time.Duration, err error) {
+	value := strings.TrimPrefix(str, "+")
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, 0, fmt.Errorf("invalid duration: %v", str)
+	}
+	value = value[1:]
+	inTime := false
+	units := ""
+	n := -1
+	for _, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			if n < 0 {
+				n = 0
+			}
+			n = n*10 + int(c-'0')
+			if n > 1e6 {
+				return 0, 0, fmt.Errorf("invalid duration: %v", str)
+			}
+			continue
+		case c == 'T' && !inTime && n < 0:
+			inTime = true
+			continue
+		}
+		if n < 0 || strings.ContainsRune(units, c) {
+			return 0, 0, fmt.Errorf("invalid duration: %v", str)
+		}
+		switch {
+		case c == 'W' && !inTime:
+			days += 7 * n
+		case c == 'D' && !inTime:
+			days += n
+		case c == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, 0, fmt.Errorf("invalid duration: %v", str)
+		}
+		units += string(c)
+		n = -1
+	}
+	if n >= 0 || strings.HasSuffix(value, "T") {
+		return 0, 0, fmt.Errorf("invalid duration: %v", str)
+	}
+	return days, d, nil
+}
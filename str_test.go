@@ -1,7 +1,10 @@
 package rrule
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestStr(t *testing.T) {
@@ -55,7 +58,7 @@ func TestSetStr(t *testing.T) {
 	if rRules[1].String() != "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TU" {
 		t.Errorf("Unexpected rrule: %s", rRules[0].String())
 	}
-	if rRules[2].String() != "FREQ=MONTHLY;UNTIL=20180520T000000Z;BYMONTHDAY=1,2,3" {
+	if rRules[2].String() != "FREQ=MONTHLY;UNTIL=20180520T235959Z;BYMONTHDAY=1,2,3" {
 		t.Errorf("Unexpected rrule: %s", rRules[2].String())
 	}
 
@@ -86,3 +89,1030 @@ func TestSetStr(t *testing.T) {
 		t.Errorf("Unexpected exDates: %v", exDates)
 	}
 }
+
+func TestSetStrWithTZID(t *testing.T) {
+	setStr := "DTSTART;TZID=America/New_York:20220101T090000\n" +
+		"RRULE:FREQ=DAILY;COUNT=2"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	newYork, _ := time.LoadLocation("America/New_York")
+	want := []time.Time{time.Date(2022, 1, 1, 9, 0, 0, 0, newYork),
+		time.Date(2022, 1, 2, 9, 0, 0, 0, newYork)}
+	value := set.All()
+	if len(value) != len(want) || !value[0].Equal(want[0]) || !value[1].Equal(want[1]) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if loc := value[0].Location().String(); loc != "America/New_York" {
+		t.Errorf("get location %v, want America/New_York", loc)
+	}
+}
+
+func TestSetStrWithDtstartLine(t *testing.T) {
+	setStr := "DTSTART:20220101T090000Z\r\n" +
+		"RRULE:FREQ=DAILY;COUNT=2\r\n" +
+		"RRULE:FREQ=WEEKLY;COUNT=2;BYHOUR=18\r\n" +
+		"EXRULE:FREQ=WEEKLY;COUNT=1\r\n"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	want := []time.Time{time.Date(2022, 1, 1, 18, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 8, 18, 0, 0, 0, time.UTC)}
+	value := set.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestStrToROptionWithTZID(t *testing.T) {
+	str := "DTSTART;TZID=Europe/Paris:20220101T090000\nRRULE:FREQ=WEEKLY;UNTIL=20220115T090000"
+	option, err := StrToROption(str)
+	if err != nil {
+		t.Fatalf("StrToROption(%q) returned error: %v", str, err)
+	}
+	paris, _ := time.LoadLocation("Europe/Paris")
+	if want := time.Date(2022, 1, 1, 9, 0, 0, 0, paris); !option.Dtstart.Equal(want) {
+		t.Errorf("get %v, want %v", option.Dtstart, want)
+	}
+	if want := time.Date(2022, 1, 15, 9, 0, 0, 0, paris); !option.Until.Equal(want) {
+		t.Errorf("get %v, want %v", option.Until, want)
+	}
+}
+
+func TestUntilInOtherZone(t *testing.T) {
+	nyc, _ := time.LoadLocation("America/New_York")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	dtstart := time.Date(2022, 1, 1, 9, 0, 0, 0, nyc)
+	// 2022-01-03 9:00 in New York is 23:00 in Tokyo, UNTIL is compared as an instant
+	cases := []struct {
+		until time.Time
+		count int
+	}{
+		{time.Date(2022, 1, 3, 23, 0, 0, 0, tokyo), 3},
+		{time.Date(2022, 1, 3, 22, 59, 59, 0, tokyo), 2},
+	}
+	for _, c := range cases {
+		r, _ := NewRRule(ROption{Freq: DAILY, Dtstart: dtstart, Until: c.until})
+		if value := r.All(); len(value) != c.count {
+			t.Errorf("UNTIL %v: get %v, want %d occurrences", c.until, value, c.count)
+		}
+	}
+	strCases := []struct {
+		str   string
+		count int
+	}{
+		// a local UNTIL is in the DTSTART zone, a UTC one is an instant
+		{"DTSTART;TZID=America/New_York:20220101T090000\nRRULE:FREQ=DAILY;UNTIL=20220103T090000", 3},
+		{"DTSTART;TZID=America/New_York:20220101T090000\nRRULE:FREQ=DAILY;UNTIL=20220103T140000Z", 3},
+		{"DTSTART;TZID=America/New_York:20220101T090000\nRRULE:FREQ=DAILY;UNTIL=20220103T135959Z", 2},
+	}
+	for _, c := range strCases {
+		r, err := StrToRRule(c.str)
+		if err != nil {
+			t.Errorf("StrToRRule(%q) returned error: %v", c.str, err)
+		} else if value := r.All(); len(value) != c.count {
+			t.Errorf("StrToRRule(%q): get %v, want %d occurrences", c.str, value, c.count)
+		}
+	}
+}
+
+func TestBadTZID(t *testing.T) {
+	cases := []string{
+		"DTSTART;TZID=Mars/Olympus_Mons:20220101T090000\nRRULE:FREQ=DAILY",
+		"DTSTART;TZID:20220101T090000\nRRULE:FREQ=DAILY",
+		"DTSTART;FOO=BAR:20220101T090000\nRRULE:FREQ=DAILY",
+	}
+	for _, item := range cases {
+		if _, e := StrToRRuleSet(item); e == nil {
+			t.Errorf("StrToRRuleSet(%q) = nil, want error", item)
+		}
+	}
+	_, e := StrToRRuleSet(cases[0])
+	if e == nil || !strings.Contains(e.Error(), "Mars/Olympus_Mons") {
+		t.Errorf("get %v, want error naming the TZID", e)
+	}
+}
+
+func TestStrToDates(t *testing.T) {
+	want := []time.Time{time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)}
+	cases := []string{
+		"VALUE=DATE:20220101,20220102",
+		"20220101,20220102",
+	}
+	for _, item := range cases {
+		value, err := StrToDates(item)
+		if err != nil {
+			t.Errorf("StrToDates(%q) returned error: %v", item, err)
+		}
+		if !timesEqual(value, want) {
+			t.Errorf("StrToDates(%q) = %v, want %v", item, value, want)
+		}
+	}
+}
+
+func TestInvalidStrToDates(t *testing.T) {
+	cases := []string{
+		"VALUE=DATE:20220101,20220102T090000Z",
+		"VALUE=DATE:20220102T090000Z",
+		"VALUE=DATE-TIME:20220101",
+		"20220101T090000Z,20220102",
+		"20220101,20220102T090000Z",
+		"VALUE=PERIOD:20220101T090000Z/PT1H",
+	}
+	for _, item := range cases {
+		if _, e := StrToDates(item); e == nil {
+			t.Errorf("StrToDates(%q) = nil, want error", item)
+		}
+	}
+}
+
+func TestSetStrWithDates(t *testing.T) {
+	setStr := "RRULE:FREQ=DAILY;COUNT=3;DTSTART=20220101\n" +
+		"EXDATE;VALUE=DATE:20220102\n" +
+		"RDATE;VALUE=DATE:20220110"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	want := []time.Time{time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)}
+	value := set.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestStrOutOfRange(t *testing.T) {
+	cases := []string{
+		"FREQ=YEARLY;BYMONTH=13",
+		"FREQ=DAILY;BYHOUR=99",
+	}
+	for _, item := range cases {
+		if _, e := StrToRRule(item); e == nil {
+			t.Errorf("StrToRRule(%q) = nil, want error", item)
+		}
+		if _, e := StrToRRuleSet("RRULE:" + item); e == nil {
+			t.Errorf("StrToRRuleSet(%q) = nil, want error", "RRULE:"+item)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	cases := []struct {
+		str      string
+		property string
+		value    string
+		msg      string
+	}{
+		{"FREQ=WEEKLY;HELLO=WORLD", "HELLO", "WORLD", "unknown RRULE property: HELLO"},
+		{"FREQ=WEEKLY;INTERVAL=abc", "INTERVAL", "abc", `INTERVAL: strconv.Atoi: parsing "abc": invalid syntax`},
+		{"FREQ=WEEKLY;COUNT=1,2", "COUNT", "1,2", `COUNT: strconv.Atoi: parsing "1,2": invalid syntax`},
+		{"FREQ=WEEKLY;BYHOUR=9,x", "BYHOUR", "9,x", `BYHOUR: strconv.Atoi: parsing "x": invalid syntax`},
+		{"FREQ=WEEKLY;BYDAY=MO,++TU", "BYDAY", "MO,++TU", `BYDAY: strconv.Atoi: parsing "++": invalid syntax`},
+		{"FREQ=WEEKLY;BYMONTH=", "BYMONTH", "", "BYMONTH option has no value"},
+		{"FREQ=HELLO", "FREQ", "HELLO", "undefined frequency: HELLO"},
+		{"FREQ", "", "FREQ", "wrong format"},
+	}
+	for _, c := range cases {
+		_, e := StrToROption(c.str)
+		var pe *ParseError
+		if !errors.As(e, &pe) {
+			t.Errorf("StrToROption(%q) = %v, want *ParseError", c.str, e)
+			continue
+		}
+		if pe.Property != c.property || pe.Value != c.value || pe.Error() != c.msg {
+			t.Errorf("StrToROption(%q) = %+v, want property %q, value %q, message %q",
+				c.str, pe, c.property, c.value, c.msg)
+		}
+	}
+}
+
+func TestSetStrFolded(t *testing.T) {
+	setStr := "RRULE:FREQ=DAILY;DTSTART=20180501T090000Z;\r\n COUNT=3;BYHOUR=9,1\r\n\t0\r\n" +
+		"EXDATE:20180502T090000Z\r\n"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	want := "RRULE:FREQ=DAILY;DTSTART=20180501T090000Z;COUNT=3;BYHOUR=9,10\nEXDATE:20180502T090000Z"
+	if value := set.String(); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+}
+
+func TestStrSkip(t *testing.T) {
+	str := "FREQ=MONTHLY;BYMONTHDAY=31;SKIP=BACKWARD"
+	r, err := StrToRRule(str)
+	if err != nil {
+		t.Fatalf("StrToRRule(%q) returned error: %v", str, err)
+	}
+	if s := r.String(); s != str {
+		t.Errorf("StrToRRule(%q).String() = %q, want %q", str, s, str)
+	}
+	if _, err = StrToRRule("FREQ=MONTHLY;SKIP=SIDEWAYS"); err == nil {
+		t.Error("get nil, want error")
+	}
+}
+
+func TestSetStrXProps(t *testing.T) {
+	setStr := "X-WR-TIMEZONE:Europe/Paris\n" +
+		"RRULE:FREQ=DAILY;DTSTART=20180501T090000Z;COUNT=3\n" +
+		"X-Vendor-Flag;param=1:Value"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	want := "RRULE:FREQ=DAILY;DTSTART=20180501T090000Z;COUNT=3\n" +
+		"X-WR-TIMEZONE:Europe/Paris\n" +
+		"X-Vendor-Flag;param=1:Value"
+	if value := set.String(); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+	if _, err = StrToRRuleSet("SUMMARY:Meeting"); err == nil {
+		t.Error("get nil, want error")
+	}
+}
+
+func TestStrWithTZID(t *testing.T) {
+	str := "DTSTART;TZID=Europe/Paris:20220101T090000\nRRULE:FREQ=DAILY;COUNT=3"
+	r, err := StrToRRule(str)
+	if err != nil {
+		t.Fatalf("StrToRRule(%q) returned error: %v", str, err)
+	}
+	if s := r.String(); s != str {
+		t.Errorf("StrToRRule(%q).String() = %q, want %q", str, s, str)
+	}
+
+	r, _ = NewRRule(ROption{Freq: DAILY, Count: 3,
+		Dtstart: time.Date(2022, 1, 1, 9, 0, 0, 0, time.FixedZone("UTC+1", 3600))})
+	if s, want := r.String(), "FREQ=DAILY;DTSTART=20220101T080000Z;COUNT=3"; s != want {
+		t.Errorf("get %q, want %q", s, want)
+	}
+}
+
+func TestSetStrWithTZIDRoundTrip(t *testing.T) {
+	setStr := "RRULE:FREQ=WEEKLY;DTSTART=20220101T090000Z;COUNT=2\n" +
+		"DTSTART;TZID=America/New_York:20220101T090000\n" +
+		"RRULE:FREQ=DAILY;COUNT=3\n" +
+		"EXDATE:20220102T140000Z"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	if value := set.String(); value != setStr {
+		t.Errorf("get %q, want %q", value, setStr)
+	}
+	if value := len(set.All()); value != 4 {
+		t.Errorf("get %v occurrences, want 4", value)
+	}
+}
+
+func TestSetStrRoundTripWithDates(t *testing.T) {
+	setStr := "DTSTART;TZID=America/New_York:20220101T090000\n" +
+		"RRULE:FREQ=DAILY;COUNT=3\n" +
+		"RDATE;TZID=America/New_York:20220110T090000\n" +
+		"RDATE:20220112T090000Z\n" +
+		"RDATE;VALUE=PERIOD:20220111T140000Z/20220111T150000Z\n" +
+		"DTSTART;TZID=Europe/Paris:20220101T090000\n" +
+		"EXRULE:FREQ=WEEKLY;COUNT=1\n" +
+		"EXDATE;TZID=America/New_York:20220102T090000\n" +
+		"EXDATE;VALUE=DATE:20220103\n" +
+		"X-FOO:bar"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	if value := set.String(); value != setStr {
+		t.Errorf("get %q, want %q", value, setStr)
+	}
+	again, err := StrToRRuleSet(set.String())
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", set.String(), err)
+	}
+	want, value := set.All(), again.All()
+	if len(value) != 4 {
+		t.Errorf("get %v, want 4 occurrences", value)
+	}
+	for i := range want {
+		if i >= len(value) || !value[i].Equal(want[i]) || value[i].Location().String() != want[i].Location().String() {
+			t.Errorf("get %v, want %v", value, want)
+			break
+		}
+	}
+}
+
+func TestParseVEVENT(t *testing.T) {
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:19970901T130000Z-123401@example.com",
+		"SUMMARY:Weekly meeting",
+		"RRULE:FREQ=WEEKLY;COUNT=3",
+		"EXDATE:20220108T090000Z",
+		"DTSTART;TZID=America/New_York:20220101T090000",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"RDATE:20220102T090000Z",
+		"END:VALARM",
+		"DESCRIPTION:a long",
+		" folded description",
+		"END:VEVENT",
+	}
+	set, err := ParseVEVENT(lines)
+	if err != nil {
+		t.Fatalf("ParseVEVENT returned error: %v", err)
+	}
+	newYork, _ := time.LoadLocation("America/New_York")
+	want := []time.Time{time.Date(2022, 1, 1, 9, 0, 0, 0, newYork),
+		time.Date(2022, 1, 8, 9, 0, 0, 0, newYork),
+		time.Date(2022, 1, 15, 9, 0, 0, 0, newYork)}
+	value := set.All()
+	if len(value) != len(want) {
+		t.Fatalf("get %v, want %v", value, want)
+	}
+	for i := range value {
+		if !value[i].Equal(want[i]) {
+			t.Errorf("get %v, want %v", value, want)
+		}
+	}
+	if len(set.GetRDate()) != 0 {
+		t.Errorf("get rdates %v, want none", set.GetRDate())
+	}
+}
+
+func TestParseVEVENTWithoutWrapper(t *testing.T) {
+	set, err := ParseVEVENT([]string{"SUMMARY:Standup", "DTSTART:20220101T090000Z", "RRULE:FREQ=DAILY;COUNT=2"})
+	if err != nil {
+		t.Fatalf("ParseVEVENT returned error: %v", err)
+	}
+	want := []time.Time{time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC)}
+	if value := set.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestParseVEVENTInVCALENDAR(t *testing.T) {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//Calendar//EN",
+		"BEGIN:VTIMEZONE",
+		"TZID:America/New_York",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20070311T020000",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+		"END:DAYLIGHT",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"DTSTART:20220101T090000Z",
+		"RRULE:FREQ=DAILY;COUNT=2",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:other@example.com",
+		"RDATE:20220301T090000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}
+	set, err := ParseVEVENT(lines)
+	if err != nil {
+		t.Fatalf("ParseVEVENT returned error: %v", err)
+	}
+	want := []time.Time{time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC)}
+	if value := set.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestStrToRRuleCountAndUntil(t *testing.T) {
+	r, err := StrToRRule("FREQ=DAILY;DTSTART=19970902T090000Z;COUNT=10;UNTIL=19970903T090000Z")
+	if err != nil {
+		t.Fatalf("StrToRRule returned error: %v", err)
+	}
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	cases := []struct {
+		str  string
+		want Weekday
+	}{
+		{"MO", MO},
+		{"+2MO", MO.Nth(2)},
+		{"-1FR", FR.Nth(-1)},
+		{"3SU", SU.Nth(3)},
+	}
+	for _, c := range cases {
+		value, err := ParseWeekday(c.str)
+		if err != nil {
+			t.Errorf("ParseWeekday(%q) returned error: %v", c.str, err)
+		} else if value != c.want {
+			t.Errorf("ParseWeekday(%q) = %v, want %v", c.str, value, c.want)
+		}
+	}
+	for _, str := range []string{"", "M", "XX", "+ZMO"} {
+		if _, err := ParseWeekday(str); err == nil {
+			t.Errorf("ParseWeekday(%q) = nil error, want error", str)
+		}
+	}
+}
+
+func TestStrToRRuleSetMalformed(t *testing.T) {
+	cases := []string{
+		"RRULE:BYDAY=,",
+		"RRULE:BYDAY=+",
+		"RRULE:BYDAY=+MO",
+		"RRULE:BYMONTH=,",
+		"RRULE:INTERVAL=\x00",
+		"RRULE:INTERVAL=\xff\xfe",
+		"RRULE:FREQ=DAILY;=",
+		"RRULE:;",
+		"RDATE:",
+		"RDATE;:20220101",
+		"EXDATE:2022",
+		"DTSTART;:20220101T090000",
+		"DTSTART::",
+	}
+	for _, s := range cases {
+		if _, err := StrToRRuleSet(s); err == nil {
+			t.Errorf("StrToRRuleSet(%q) = nil error, want error", s)
+		}
+	}
+}
+
+func TestDtstartYearZero(t *testing.T) {
+	set, err := StrToRRuleSet("RRULE:FREQ=WEEKLY;DTSTART=00000101;COUNT=2")
+	if err != nil {
+		t.Fatalf("StrToRRuleSet returned error: %v", err)
+	}
+	want := []time.Time{time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(0, 1, 8, 0, 0, 0, 0, time.UTC)}
+	if value := set.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestDateOnlyUntil(t *testing.T) {
+	r, err := StrToRRule("FREQ=DAILY;DTSTART=20221229T230000Z;UNTIL=20221231")
+	if err != nil {
+		t.Fatalf("StrToRRule returned error: %v", err)
+	}
+	want := []time.Time{time.Date(2022, 12, 29, 23, 0, 0, 0, time.UTC),
+		time.Date(2022, 12, 30, 23, 0, 0, 0, time.UTC),
+		time.Date(2022, 12, 31, 23, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestDateOnlyUntilWithTZID(t *testing.T) {
+	// the end of the day is taken in the DTSTART zone, not in UTC
+	set, err := StrToRRuleSet("DTSTART;TZID=America/New_York:20221229T230000\nRRULE:FREQ=DAILY;UNTIL=20221231")
+	if err != nil {
+		t.Fatalf("StrToRRuleSet returned error: %v", err)
+	}
+	newYork, _ := time.LoadLocation("America/New_York")
+	value := set.All()
+	if len(value) != 3 || !value[2].Equal(time.Date(2022, 12, 31, 23, 0, 0, 0, newYork)) {
+		t.Errorf("get %v, want 3 occurrences ending 2022-12-31 23:00 in New York", value)
+	}
+}
+
+func TestStrToPeriods(t *testing.T) {
+	str := "VALUE=PERIOD:19970101T180000Z/19970102T070000Z,19970109T180000Z/PT5H30M,19970110T180000Z/P1DT1H,19970111T180000Z/P1W"
+	value, err := StrToPeriods(str)
+	if err != nil {
+		t.Fatalf("StrToPeriods(%q) returned error: %v", str, err)
+	}
+	want := []Occurrence{
+		{time.Date(1997, 1, 1, 18, 0, 0, 0, time.UTC), time.Date(1997, 1, 2, 7, 0, 0, 0, time.UTC)},
+		{time.Date(1997, 1, 9, 18, 0, 0, 0, time.UTC), time.Date(1997, 1, 9, 23, 30, 0, 0, time.UTC)},
+		{time.Date(1997, 1, 10, 18, 0, 0, 0, time.UTC), time.Date(1997, 1, 11, 19, 0, 0, 0, time.UTC)},
+		{time.Date(1997, 1, 11, 18, 0, 0, 0, time.UTC), time.Date(1997, 1, 18, 18, 0, 0, 0, time.UTC)},
+	}
+	if len(value) != len(want) {
+		t.Fatalf("get %v, want %v", value, want)
+	}
+	for i := range value {
+		if value[i] != want[i] {
+			t.Errorf("get %v, want %v", value[i], want[i])
+		}
+	}
+}
+
+func TestStrToPeriodsInvalid(t *testing.T) {
+	cases := []string{
+		"VALUE=PERIOD:19970101T180000Z/19970102T070000Z,19970103T180000Z",
+		"VALUE=DATE:19970101T180000Z/19970102T070000Z",
+		"VALUE=PERIOD:19970101T180000Z/19970101T170000Z",
+		"VALUE=PERIOD:19970101T180000Z/P",
+		"VALUE=PERIOD:19970101T180000Z/PT",
+		"VALUE=PERIOD:19970101T180000Z/P1H",
+		"VALUE=PERIOD:19970101T180000Z/PT1D",
+		"VALUE=PERIOD:19970101T180000Z/PT1H1H",
+		"VALUE=PERIOD:19970101T180000Z/P1DT",
+		"VALUE=PERIOD:19970101T180000Z/-PT1H",
+		"VALUE=PERIOD:19970101T180000Z/P1",
+	}
+	for _, str := range cases {
+		if _, err := StrToPeriods(str); err == nil {
+			t.Errorf("StrToPeriods(%q) = nil error, want error", str)
+		}
+	}
+	if _, err := StrToDates("19970101T180000Z/19970102T070000Z"); err == nil {
+		t.Errorf("StrToDates of a period = nil error, want error")
+	}
+}
+
+func TestSetStrWithPeriods(t *testing.T) {
+	setStr := "RRULE:FREQ=DAILY;DTSTART=19970101T090000Z;COUNT=2\n" +
+		"RDATE;VALUE=PERIOD:19970101T180000Z/PT1H,19970103T180000Z/19970103T200000Z"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	want := []time.Time{time.Date(1997, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 1, 1, 18, 0, 0, 0, time.UTC),
+		time.Date(1997, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 1, 3, 18, 0, 0, 0, time.UTC)}
+	if value := set.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	wantStr := "RRULE:FREQ=DAILY;DTSTART=19970101T090000Z;COUNT=2\n" +
+		"RDATE;VALUE=PERIOD:19970101T180000Z/19970101T190000Z\n" +
+		"RDATE;VALUE=PERIOD:19970103T180000Z/19970103T200000Z"
+	if value := set.String(); value != wantStr {
+		t.Errorf("get %q, want %q", value, wantStr)
+	}
+	if _, err := StrToRRuleSet("RDATE;VALUE=PERIOD:19970101T180000Z/PT1H,19970103T180000Z"); err == nil {
+		t.Errorf("StrToRRuleSet of mixed RDATE = nil error, want error")
+	}
+}
+
+func TestStrToRRuleBysetposAlone(t *testing.T) {
+	if _, err := StrToRRule("FREQ=MONTHLY;BYSETPOS=1"); err == nil || !strings.Contains(err.Error(), "BYSETPOS") {
+		t.Errorf("get %v, want error naming BYSETPOS", err)
+	}
+}
+
+func TestStrToRRuleInvalidInterval(t *testing.T) {
+	for _, str := range []string{"FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;INTERVAL=-2"} {
+		if _, err := StrToRRule(str); err == nil {
+			t.Errorf("StrToRRule(%q) = nil error, want error", str)
+		}
+	}
+}
+
+func TestRFCString(t *testing.T) {
+	r, _ := StrToRRule("FREQ=DAILY;DTSTART=20220101T080000Z;COUNT=2")
+	want := "RRULE:FREQ=DAILY;DTSTART=20220101T080000Z;COUNT=2"
+	if value := r.RFCString(); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+	if value := r.String(); value != want[len("RRULE:"):] {
+		t.Errorf("get %q, want %q", value, want[len("RRULE:"):])
+	}
+	set := Set{}
+	set.RRule(r)
+	if value := set.Recurrence(); len(value) != 1 || value[0] != want {
+		t.Errorf("get %q, want [%q]", value, want)
+	}
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+	r, _ = NewRRule(ROption{Freq: DAILY, Dtstart: time.Date(2022, 1, 1, 9, 0, 0, 0, paris)})
+	want = "DTSTART;TZID=Europe/Paris:20220101T090000\nRRULE:FREQ=DAILY"
+	if value := r.RFCString(); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+}
+
+func TestRFCStringRoundTrip(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	for _, dtstart := range []time.Time{
+		time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 1, 9, 0, 0, 0, paris),
+	} {
+		r, _ := NewRRule(ROption{Freq: DAILY, Count: 2, Dtstart: dtstart})
+		value, err := StrToRRule(r.RFCString())
+		if err != nil {
+			t.Errorf("StrToRRule(%q) returned error: %v", r.RFCString(), err)
+			continue
+		}
+		got, want := value.All(), r.All()
+		if len(got) != len(want) || !got[0].Equal(want[0]) || !got[1].Equal(want[1]) {
+			t.Errorf("%q: get %v, want %v", r.RFCString(), got, want)
+		}
+	}
+	if _, err := StrToROption("rrule:FREQ=DAILY;COUNT=2"); err != nil {
+		t.Errorf("StrToROption returned error: %v", err)
+	}
+}
+
+func TestStrToRRuleWithSpaces(t *testing.T) {
+	set, err := StrToRRuleSet("RRULE: FREQ=DAILY ; COUNT = 2 ;DTSTART= 20220101T090000Z")
+	if err != nil {
+		t.Fatalf("StrToRRuleSet returned error: %v", err)
+	}
+	want := []time.Time{time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 9, 0, 0, 0, time.UTC)}
+	if value := set.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	for _, str := range []string{"FREQ=DAILY; =2", "FREQ=DAILY;COUNT= "} {
+		if _, err := StrToRRule(str); err == nil {
+			t.Errorf("StrToRRule(%q) = nil error, want error", str)
+		}
+	}
+}
+
+func TestStrToDatesWithTZID(t *testing.T) {
+	chicago, _ := time.LoadLocation("America/Chicago")
+	value, err := StrToDates("TZID=America/Chicago:20220101T090000,20220102T090000")
+	if err != nil {
+		t.Fatalf("StrToDates returned error: %v", err)
+	}
+	want := []time.Time{time.Date(2022, 1, 1, 9, 0, 0, 0, chicago),
+		time.Date(2022, 1, 2, 9, 0, 0, 0, chicago)}
+	if len(value) != 2 || !value[0].Equal(want[0]) || !value[1].Equal(want[1]) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if _, err := StrToDates("TZID=Mars/Olympus_Mons:20220101T090000"); err == nil {
+		t.Errorf("StrToDates with unknown TZID = nil error, want error")
+	}
+}
+
+func TestSetStrExdateWithTZID(t *testing.T) {
+	setStr := "DTSTART;TZID=America/Chicago:20220101T090000\n" +
+		"RRULE:FREQ=DAILY;COUNT=3\n" +
+		"EXDATE;TZID=America/Chicago:20220102T090000"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	chicago, _ := time.LoadLocation("America/Chicago")
+	want := []time.Time{time.Date(2022, 1, 1, 9, 0, 0, 0, chicago),
+		time.Date(2022, 1, 3, 9, 0, 0, 0, chicago)}
+	value := set.All()
+	if len(value) != 2 || !value[0].Equal(want[0]) || !value[1].Equal(want[1]) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestParseFrequency(t *testing.T) {
+	for _, freq := range []Frequency{YEARLY, MONTHLY, WEEKLY, DAILY, HOURLY, MINUTELY, SECONDLY} {
+		if value, err := ParseFrequency(freq.String()); err != nil || value != freq {
+			t.Errorf("get %v, %v, want %v", value, err, freq)
+		}
+	}
+	if value, err := ParseFrequency(" weekly\n"); err != nil || value != WEEKLY {
+		t.Errorf("get %v, %v, want %v", value, err, WEEKLY)
+	}
+	if _, err := ParseFrequency("FORTNIGHTLY"); err == nil {
+		t.Errorf("ParseFrequency(\"FORTNIGHTLY\") = nil error, want error")
+	}
+}
+
+func TestStrToROptionLowercase(t *testing.T) {
+	option, err := StrToROption("freq=weekly;count=3;byday=mo,+2fr;wkst=su;dtstart=20220101t090000z;skip=forward")
+	if err != nil {
+		t.Fatalf("StrToROption returned error: %v", err)
+	}
+	want := "FREQ=WEEKLY;DTSTART=20220101T090000Z;WKST=SU;COUNT=3;BYDAY=MO,+2FR;SKIP=FORWARD"
+	if value := option.String(); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+}
+
+func TestStringWithOptions(t *testing.T) {
+	option := ROption{Freq: DAILY, Dtstart: time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC), Count: 2}
+	want := "FREQ=DAILY;DTSTART=20220101T080000Z;INTERVAL=1;WKST=MO;COUNT=2"
+	if value := option.StringWithOptions(SerializeOptions{}); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+	want = "FREQ=DAILY;DTSTART=20220101T080000Z;COUNT=2"
+	if value := option.StringWithOptions(SerializeOptions{OmitDefaults: true}); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+	option.Interval = 1
+	if value := option.StringWithOptions(SerializeOptions{OmitDefaults: true}); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+	option.Interval = 2
+	option.Wkst = SU
+	want = "FREQ=DAILY;DTSTART=20220101T080000Z;INTERVAL=2;WKST=SU;COUNT=2"
+	if value := option.StringWithOptions(SerializeOptions{OmitDefaults: true}); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+	if value := option.String(); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+}
+
+func TestStrKeepsExplicitWkst(t *testing.T) {
+	str := "FREQ=WEEKLY;DTSTART=20220101T080000Z;WKST=MO;COUNT=2"
+	r, _ := StrToRRule(str)
+	if value := r.String(); value != str {
+		t.Errorf("get %q, want %q", value, str)
+	}
+	want := "FREQ=WEEKLY;DTSTART=20220101T080000Z;COUNT=2"
+	r, _ = StrToRRule(want)
+	if value := r.String(); value != want {
+		t.Errorf("get %q, want %q", value, want)
+	}
+	option, _ := NewBuilder(WEEKLY).Count(2).Wkst(MO).Dtstart(time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)).Build()
+	if value := option.String(); value != str {
+		t.Errorf("get %q, want %q", value, str)
+	}
+}
+
+func TestStrToRRuleLenient(t *testing.T) {
+	want := "FREQ=DAILY;DTSTART=20220101T090000Z;COUNT=3;BYHOUR=9,10"
+	for _, str := range []string{
+		"FREQ=DAILY;DTSTART=20220101T090000Z;COUNT=3;BYHOUR=9,10;",
+		"FREQ=DAILY;;DTSTART=20220101T090000Z;COUNT=3; ;BYHOUR=9,10",
+		"FREQ=DAILY;DTSTART=20220101T090000Z;\r\n COUNT=3;BYHOUR=9,\n 10",
+	} {
+		r, err := StrToRRule(str)
+		if err != nil {
+			t.Errorf("StrToRRule(%q) returned error: %v", str, err)
+		} else if value := r.String(); value != want {
+			t.Errorf("StrToRRule(%q).String() = %q, want %q", str, value, want)
+		}
+	}
+	if _, err := StrToRRule(";"); err == nil {
+		t.Errorf("StrToRRule(%q) = nil error, want error", ";")
+	}
+}
+
+func TestSetStrExDateDay(t *testing.T) {
+	setStr := "DTSTART;TZID=America/New_York:20220228T090000\n" +
+		"RRULE:FREQ=DAILY;COUNT=3;BYHOUR=9,21\n" +
+		"EXDATE;VALUE=DATE:20220301"
+	set, err := StrToRRuleSet(setStr)
+	if err != nil {
+		t.Fatalf("StrToRRuleSet(%q) returned error: %v", setStr, err)
+	}
+	// 2022-03-01 21:00 in New York is 2022-03-02 in UTC, it's still excluded
+	nyc, _ := time.LoadLocation("America/New_York")
+	want := []time.Time{time.Date(2022, 2, 28, 9, 0, 0, 0, nyc),
+		time.Date(2022, 2, 28, 21, 0, 0, 0, nyc)}
+	value := set.All()
+	if len(value) != len(want) || !value[0].Equal(want[0]) || !value[1].Equal(want[1]) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	wantStr := "DTSTART;TZID=America/New_York:20220228T090000\n" +
+		"RRULE:FREQ=DAILY;COUNT=3;BYHOUR=9,21\n" +
+		"EXDATE;VALUE=DATE:20220301"
+	if value := set.String(); value != wantStr {
+		t.Errorf("get %q, want %q", value, wantStr)
+	}
+}
+
+func TestStrToRRuleWithStart(t *testing.T) {
+	nyc, _ := time.LoadLocation("America/New_York")
+	dtstart := time.Date(2022, 1, 1, 9, 0, 0, 0, nyc)
+	r, err := StrToRRuleWithStart("FREQ=DAILY;COUNT=2;UNTIL=20220105T090000", dtstart)
+	if err != nil {
+		t.Fatalf("StrToRRuleWithStart returned error: %v", err)
+	}
+	want := []time.Time{dtstart, time.Date(2022, 1, 2, 9, 0, 0, 0, nyc)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := r.OrigOptions.Until; !value.Equal(time.Date(2022, 1, 5, 9, 0, 0, 0, nyc)) {
+		t.Errorf("get %v, want UNTIL in the DTSTART zone", value)
+	}
+	if _, err := StrToRRuleWithStart("FREQ=DAILY;DTSTART=20220101T140000Z", dtstart); err != nil {
+		t.Errorf("StrToRRuleWithStart with the same DTSTART returned error: %v", err)
+	}
+	if _, err := StrToRRuleWithStart("FREQ=DAILY;DTSTART=20220101T090000Z", dtstart); err == nil {
+		t.Errorf("StrToRRuleWithStart with another DTSTART = nil error, want error")
+	}
+}
+
+func TestPropertyLines(t *testing.T) {
+	set, err := StrSliceToRRuleSet([]string{
+		string(PropDTStart) + ":20220101T090000Z",
+		string(PropRRule) + ":FREQ=DAILY;COUNT=3",
+		string(PropExDate) + ":20220102T090000Z",
+	})
+	if err != nil {
+		t.Fatalf("StrSliceToRRuleSet returned error: %v", err)
+	}
+	want := []time.Time{
+		time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 3, 9, 0, 0, 0, time.UTC),
+	}
+	if value := set.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := set.Recurrence(); !strings.HasPrefix(value[0], string(PropDTStart)+":") {
+		t.Errorf("get %v, want %s line first", value, PropDTStart)
+	}
+}
+
+func TestStrSliceToRRuleSetDTStartRoundTrip(t *testing.T) {
+	lines := []string{"DTSTART:20220101T090000Z", "RRULE:FREQ=DAILY;COUNT=2"}
+	set, err := StrSliceToRRuleSet(lines)
+	if err != nil {
+		t.Fatalf("StrSliceToRRuleSet returned error: %v", err)
+	}
+	if value, want := set.DTStart(), time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC); !value.Equal(want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := set.Recurrence(); strings.Join(value, "\n") != strings.Join(lines, "\n") {
+		t.Errorf("get %v, want %v", value, lines)
+	}
+}
+
+func TestStrToRRulePaddedOrdinals(t *testing.T) {
+	r, err := StrToRRule("FREQ=MONTHLY;COUNT=4;BYDAY=+01MO,-01FR;DTSTART=19970902T090000Z")
+	if err != nil {
+		t.Fatalf("StrToRRule returned error: %v", err)
+	}
+	want := "FREQ=MONTHLY;DTSTART=19970902T090000Z;COUNT=4;BYDAY=+1MO,-1FR"
+	if value := r.String(); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	wantTimes := []time.Time{time.Date(1997, 9, 26, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 10, 6, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 10, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 11, 3, 9, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, wantTimes) {
+		t.Errorf("get %v, want %v", value, wantTimes)
+	}
+	for _, str := range []string{"+1MO", "+01MO", "1MO", "01MO"} {
+		if value, err := ParseWeekday(str); err != nil || value != MO.Nth(1) {
+			t.Errorf("ParseWeekday(%q) = %v, %v, want %v", str, value, err, MO.Nth(1))
+		}
+	}
+}
+
+func TestUntilValueType(t *testing.T) {
+	// an all-day rule keeps DATE values, a timed one has a UTC date-time UNTIL
+	cases := []struct {
+		str  string
+		want string
+	}{
+		{"FREQ=DAILY;DTSTART=20220101;UNTIL=20220103",
+			"DTSTART;VALUE=DATE:20220101\nRRULE:FREQ=DAILY;UNTIL=20220103"},
+		{"FREQ=DAILY;DTSTART=20220101;UNTIL=20220103T120000Z",
+			"DTSTART;VALUE=DATE:20220101\nRRULE:FREQ=DAILY;UNTIL=20220103"},
+		{"FREQ=DAILY;DTSTART=20220101T090000Z;UNTIL=20220103",
+			"FREQ=DAILY;DTSTART=20220101T090000Z;UNTIL=20220103T235959Z"},
+	}
+	for _, c := range cases {
+		r, err := StrToRRule(c.str)
+		if err != nil {
+			t.Errorf("StrToRRule(%q) returned error: %v", c.str, err)
+			continue
+		}
+		if value := r.String(); value != c.want {
+			t.Errorf("get %v, want %v", value, c.want)
+		}
+		parsed, err := StrToRRule(r.String())
+		if err != nil {
+			t.Errorf("StrToRRule(%q) returned error: %v", r.String(), err)
+			continue
+		}
+		if value, want := parsed.All(), r.All(); !timesEqual(value, want) {
+			t.Errorf("get %v, want %v", value, want)
+		}
+	}
+
+	option := ROption{Freq: WEEKLY,
+		AllDay:  true,
+		Dtstart: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until:   time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC)}
+	want := "DTSTART;VALUE=DATE:20220101\nRRULE:FREQ=WEEKLY;UNTIL=20220115"
+	if value := option.String(); value != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestStrAllDay(t *testing.T) {
+	str := "DTSTART;VALUE=DATE:20220101\nRRULE:FREQ=WEEKLY;COUNT=3"
+	r, err := StrToRRule(str)
+	if err != nil {
+		t.Fatalf("StrToRRule(%q) returned error: %v", str, err)
+	}
+	want := []time.Time{time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 8, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := r.String(); value != str {
+		t.Errorf("get %v, want %v", value, str)
+	}
+
+	set, err := StrToRRuleSet(str + "\nEXDATE;VALUE=DATE:20220108")
+	if err != nil {
+		t.Fatalf("StrToRRuleSet returned error: %v", err)
+	}
+	want = []time.Time{want[0], want[2]}
+	if value := set.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := set.String(); value != str+"\nEXDATE;VALUE=DATE:20220108" {
+		t.Errorf("get %v, want %v", value, str+"\nEXDATE;VALUE=DATE:20220108")
+	}
+
+	// the time of an AllDay DTSTART is dropped
+	r, _ = NewRRule(ROption{Freq: DAILY, Count: 2, AllDay: true,
+		Dtstart: time.Date(2022, 1, 1, 9, 30, 0, 0, time.UTC)})
+	want = []time.Time{time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)}
+	if value := r.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+
+	if _, err := StrToRRule("DTSTART;VALUE=DATE:20220101T090000Z\nRRULE:FREQ=WEEKLY"); err == nil {
+		t.Errorf("StrToRRule with a date-time VALUE=DATE = nil error, want error")
+	}
+}
+
+func TestSetStrMultipleDateLines(t *testing.T) {
+	set, err := StrSliceToRRuleSet([]string{
+		"RDATE:19970904T090000Z",
+		"RRULE:FREQ=DAILY;DTSTART=19970902T090000Z;COUNT=2",
+		"RDATE:19970906T090000Z,19970907T090000Z",
+		"EXDATE:19970902T090000Z",
+		"RRULE:FREQ=DAILY;DTSTART=19970910T090000Z;COUNT=1",
+		"RDATE:19970908T090000Z",
+		"EXDATE:19970907T090000Z",
+	})
+	if err != nil {
+		t.Fatalf("StrSliceToRRuleSet returned error: %v", err)
+	}
+	want := []time.Time{time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 6, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 10, 9, 0, 0, 0, time.UTC)}
+	if value := set.All(); !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if value := set.GetRDate(); len(value) != 4 {
+		t.Errorf("get %v, want the 4 rdates of the 3 lines", value)
+	}
+	if value := set.GetExDate(); len(value) != 2 {
+		t.Errorf("get %v, want the 2 exdates of the 2 lines", value)
+	}
+}
+
+func TestSetParseErrorLine(t *testing.T) {
+	cases := []struct {
+		parse    func() error
+		line     int
+		property string
+	}{
+		{func() error {
+			_, err := StrSliceToRRuleSet([]string{"DTSTART:19970902T090000Z", "", "RRULE:FREQ=DAILY;INTERVAL=x"})
+			return err
+		}, 3, "INTERVAL"},
+		{func() error {
+			_, err := StrSliceToRRuleSet([]string{"RRULE:FREQ=DAILY;BYMONTH=13"})
+			return err
+		}, 1, "RRULE"},
+		{func() error {
+			_, err := StrToRRuleSet("RRULE:FREQ=DAILY;\r\n COUNT=2\r\nRDATE:19970902T090000Z\r\nEXDATE:1997")
+			return err
+		}, 4, "EXDATE"},
+		{func() error {
+			_, err := ParseVEVENT([]string{"BEGIN:VEVENT", "SUMMARY:Meeting", "RRULE:FREQ=DAILY;",
+				" COUNT=2", "DTSTART:19970902T090000Z", "RDATE:bad", "END:VEVENT"})
+			return err
+		}, 6, "RDATE"},
+		{func() error {
+			_, err := StrSliceToRRuleSet([]string{"RRULE:FREQ=DAILY", "VEVENT"})
+			return err
+		}, 2, ""},
+	}
+	for _, c := range cases {
+		err := c.parse()
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("get %v, want *ParseError", err)
+			continue
+		}
+		if pe.Line != c.line || pe.Property != c.property {
+			t.Errorf("get line %d, property %q, want line %d, property %q", pe.Line, pe.Property, c.line, c.property)
+		}
+	}
+	_, err := StrSliceToRRuleSet([]string{"RRULE:FREQ=DAILY;INTERVAL=x"})
+	var pe *ParseError
+	if !errors.As(err, &pe) || errors.As(pe.Err, new(*ParseError)) || pe.Property != "INTERVAL" || pe.Line != 1 {
+		t.Errorf("get %v, want a *ParseError of the RRULE part INTERVAL, not wrapped", err)
+	}
+}
@@ -0,0 +1,134 @@
+package rrule
+
+import (
+	"strings"
+	"time"
+)
+
+// words joins the non empty items with spaces.
+func words(items ...string) string {
+	var result []string
+	for _, item := range items {
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+// joinText joins items as "a", "a and b" or "a, b and c".
+func joinText(loc Locale, items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return words(strings.Join(items[:len(items)-1], ", "), loc.And(), items[len(items)-1])
+}
+
+func isWeekdays(wdays []Weekday) bool {
+	if len(wdays) != 5 {
+		return false
+	}
+	for _, wday := range wdays {
+		if wday.n != 0 || wday.weekday > FR.weekday {
+			return false
+		}
+	}
+	return true
+}
+
+func weekdaysText(loc Locale, wdays []Weekday) string {
+	items := make([]string, len(wdays))
+	for i, wday := range wdays {
+		if wday.n == 0 {
+			items[i] = loc.Weekday(wday.Day())
+		} else {
+			items[i] = words(loc.The(), loc.Ordinal(wday.n), loc.Weekday(wday.Day()))
+		}
+	}
+	return joinText(loc, items)
+}
+
+func monthdaysText(loc Locale, mdays []int) string {
+	items := make([]string, len(mdays))
+	for i, mday := range mdays {
+		if mday < 0 {
+			items[i] = words(loc.Ordinal(mday), loc.Day())
+		} else {
+			items[i] = loc.Ordinal(mday)
+		}
+	}
+	return words(loc.The(), joinText(loc, items))
+}
+
+func monthsText(loc Locale, months []int) string {
+	items := make([]string, len(months))
+	for i, month := range months {
+		items[i] = loc.Month(time.Month(month))
+	}
+	return joinText(loc, items)
+}
+
+// Text returns an English description of the option, ex. "every 2 weeks on Monday and Friday".
+// It covers FREQ, INTERVAL, BYDAY, BYMONTHDAY, BYMONTH, COUNT and UNTIL,
+// other parts are appended in their RRULE form.
+func (option *ROption) Text() string {
+	return option.TextInLocale(English)
+}
+
+// TextInLocale is like Text, with the words of the given locale.
+func (option *ROption) TextInLocale(loc Locale) string {
+	interval := option.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	wdays := option.Byweekday
+	var result []string
+	if interval == 1 && (option.Freq == WEEKLY || option.Freq == DAILY) && isWeekdays(wdays) {
+		result = append(result, loc.EveryWeekday())
+		wdays = nil
+	} else {
+		result = append(result, loc.Every(option.Freq, interval))
+	}
+	if len(option.Bymonth) != 0 {
+		result = append(result, loc.In(), monthsText(loc, option.Bymonth))
+	}
+	switch {
+	case len(wdays) != 0 && len(option.Bymonthday) != 0:
+		result = append(result, loc.On(), weekdaysText(loc, wdays), monthdaysText(loc, option.Bymonthday))
+	case isWeekdays(wdays):
+		result = append(result, loc.OnWeekdays())
+	case len(wdays) != 0:
+		result = append(result, loc.On(), weekdaysText(loc, wdays))
+	case len(option.Bymonthday) != 0:
+		result = append(result, loc.On(), monthdaysText(loc, option.Bymonthday))
+	}
+	if option.Count != 0 {
+		result = append(result, loc.Times(option.Count))
+	}
+	if !option.Until.IsZero() {
+		result = append(result, loc.Until(option.Until))
+	}
+	var rest []string
+	rest = appendIntsOption(rest, "BYSETPOS", option.Bysetpos)
+	rest = appendIntsOption(rest, "BYYEARDAY", option.Byyearday)
+	rest = appendIntsOption(rest, "BYWEEKNO", option.Byweekno)
+	rest = appendIntsOption(rest, "BYHOUR", option.Byhour)
+	rest = appendIntsOption(rest, "BYMINUTE", option.Byminute)
+	rest = appendIntsOption(rest, "BYSECOND", option.Bysecond)
+	rest = appendIntsOption(rest, "BYEASTER", option.Byeaster)
+	if len(rest) != 0 {
+		result = append(result, "("+strings.Join(rest, ";")+")")
+	}
+	return words(result...)
+}
+
+// Text returns an English description of the RRule, see ROption.Text.
+func (r *RRule) Text() string {
+	return r.OrigOptions.Text()
+}
+
+// TextInLocale returns a description of the RRule with the words of the given locale,
+// see ROption.Text.
+func (r *RRule) TextInLocale(loc Locale) string {
+	return r.OrigOptions.TextInLocale(loc)
+}
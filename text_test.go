@@ -0,0 +1,91 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestText(t *testing.T) {
+	cases := []struct {
+		str  string
+		want string
+	}{
+		{"FREQ=DAILY", "every day"},
+		{"FREQ=DAILY;INTERVAL=3", "every 3 days"},
+		{"FREQ=HOURLY;INTERVAL=2", "every 2 hours"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "every 2 weeks on Monday and Friday"},
+		{"FREQ=WEEKLY;BYDAY=MO,WE,FR", "every week on Monday, Wednesday and Friday"},
+		{"FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "every weekday"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TU,WE,TH,FR", "every 2 weeks on weekdays"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15", "every month on the 1st and 15th"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", "every month on the last day"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-2", "every month on the 2nd last day"},
+		{"FREQ=MONTHLY;BYDAY=+2TU", "every month on the 2nd Tuesday"},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "every month on the last Friday"},
+		{"FREQ=MONTHLY;BYDAY=+1MO,-1MO", "every month on the 1st Monday and the last Monday"},
+		{"FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13", "every month on Friday the 13th"},
+		{"FREQ=YEARLY;BYMONTH=1,7", "every year in January and July"},
+		{"FREQ=YEARLY;BYMONTH=11;BYDAY=+4TH", "every year in November on the 4th Thursday"},
+		{"FREQ=MONTHLY;BYMONTHDAY=21,22,23,11", "every month on the 21st, 22nd, 23rd and 11th"},
+		{"FREQ=DAILY;COUNT=1", "every day for 1 time"},
+		{"FREQ=DAILY;COUNT=10", "every day for 10 times"},
+		{"FREQ=WEEKLY;UNTIL=20221231T000000Z", "every week until December 31, 2022"},
+		{"FREQ=MONTHLY;BYDAY=MO;BYSETPOS=-1", "every month on Monday (BYSETPOS=-1)"},
+	}
+	for _, c := range cases {
+		r, err := StrToRRule(c.str)
+		if err != nil {
+			t.Fatalf("StrToRRule(%q) returned error: %v", c.str, err)
+		}
+		if value := r.Text(); value != c.want {
+			t.Errorf("StrToRRule(%q).Text() = %q, want %q", c.str, value, c.want)
+		}
+	}
+}
+
+func TestTextInLocale(t *testing.T) {
+	cases := []struct {
+		str     string
+		german  string
+		spanish string
+	}{
+		{"FREQ=DAILY", "jeden Tag", "cada día"},
+		{"FREQ=YEARLY;INTERVAL=2", "alle 2 Jahre", "cada 2 años"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "alle 2 Wochen am Montag und Freitag", "cada 2 semanas el lunes y viernes"},
+		{"FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "jeden Werktag", "cada día laborable"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TU,WE,TH,FR", "alle 2 Wochen an Werktagen", "cada 2 semanas en días laborables"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15", "jeden Monat am 1. und 15.", "cada mes el 1º y 15º"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", "jeden Monat am letzten Tag", "cada mes el último día"},
+		{"FREQ=MONTHLY;BYDAY=-2FR", "jeden Monat am vorletzten Freitag", "cada mes el penúltimo viernes"},
+		{"FREQ=YEARLY;BYMONTH=3;BYDAY=+4TH", "jedes Jahr im März am 4. Donnerstag", "cada año en marzo el 4º jueves"},
+		{"FREQ=DAILY;COUNT=10", "jeden Tag für 10 Mal", "cada día durante 10 veces"},
+		{"FREQ=WEEKLY;UNTIL=20221231T000000Z", "jede Woche bis 31. Dezember 2022", "cada semana hasta el 31 de diciembre de 2022"},
+		{"FREQ=MONTHLY;BYDAY=MO;BYSETPOS=-1", "jeden Monat am Montag (BYSETPOS=-1)", "cada mes el lunes (BYSETPOS=-1)"},
+	}
+	for _, c := range cases {
+		r, err := StrToRRule(c.str)
+		if err != nil {
+			t.Fatalf("StrToRRule(%q) returned error: %v", c.str, err)
+		}
+		if value := r.TextInLocale(German); value != c.german {
+			t.Errorf("StrToRRule(%q).TextInLocale(German) = %q, want %q", c.str, value, c.german)
+		}
+		if value := r.TextInLocale(Spanish); value != c.spanish {
+			t.Errorf("StrToRRule(%q).TextInLocale(Spanish) = %q, want %q", c.str, value, c.spanish)
+		}
+		if value := r.TextInLocale(English); value != r.Text() {
+			t.Errorf("StrToRRule(%q).TextInLocale(English) = %q, want %q", c.str, value, r.Text())
+		}
+	}
+}
+
+func TestLocaleMonthOutOfRange(t *testing.T) {
+	for _, locale := range []Locale{English, German, Spanish} {
+		if value, want := locale.Month(13), time.Month(13).String(); value != want {
+			t.Errorf("%T.Month(13) = %q, want %q", locale, value, want)
+		}
+	}
+	if value := German.Month(time.March); value != "März" {
+		t.Errorf("get %q, want %q", value, "März")
+	}
+}
@@ -1,8 +1,10 @@
 package rrule
 
 import (
+	"context"
 	"errors"
 	"math"
+	"sort"
 	"time"
 )
 
@@ -40,6 +42,13 @@ func daysIn(m time.Month, year int) int {
 	return time.Date(year, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
 
+// daysBetween returns the number of calendar days from the date of a to the date of b.
+func daysBetween(a, b time.Time) int {
+	start := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start) / (24 * time.Hour))
+}
+
 // mod in Python
 func pymod(a, b int) int {
 	r := a % b
@@ -73,6 +82,92 @@ func timeContains(list []time.Time, elem time.Time) bool {
 	return false
 }
 
+// stringContains reports whether list contains elem.
+func stringContains(list []string, elem string) bool {
+	for _, s := range list {
+		if s == elem {
+			return true
+		}
+	}
+	return false
+}
+
+// instantContains is like timeContains but compares instants, regardless of location.
+func instantContains(list []time.Time, elem time.Time) bool {
+	for _, t := range list {
+		if t.Equal(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+// periodContains is like instantContains for periods.
+func periodContains(list []Occurrence, elem Occurrence) bool {
+	for _, p := range list {
+		if p.Start.Equal(elem.Start) && p.End.Equal(elem.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueSorted returns a sorted copy of list without duplicates.
+func uniqueSorted(list []int) []int {
+	if len(list) == 0 {
+		return nil
+	}
+	result := append([]int{}, list...)
+	sort.Ints(result)
+	n := 1
+	for _, v := range result[1:] {
+		if v != result[n-1] {
+			result[n] = v
+			n++
+		}
+	}
+	return result[:n]
+}
+
+// uniqueSortedTimes returns a chronologically sorted copy of list
+// without the times equal, as instants, to a previous one.
+func uniqueSortedTimes(list []time.Time) []time.Time {
+	if len(list) == 0 {
+		return nil
+	}
+	result := append([]time.Time{}, list...)
+	sort.Stable(timeSlice(result))
+	n := 1
+	for _, t := range result[1:] {
+		if !t.Equal(result[n-1]) {
+			result[n] = t
+			n++
+		}
+	}
+	return result[:n]
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func weekdayContains(list []Weekday, elem Weekday) bool {
+	for _, t := range list {
+		if t == elem {
+			return true
+		}
+	}
+	return false
+}
+
 func repeat(value, count int) []int {
 	result := []int{}
 	for i := 0; i < count; i++ {
@@ -119,6 +214,17 @@ func timeSliceIterator(s []time.Time) func() (time.Time, bool) {
 	}
 }
 
+func reverseTimeSliceIterator(s []time.Time) func() (time.Time, bool) {
+	index := len(s)
+	return func() (time.Time, bool) {
+		if index <= 0 {
+			return time.Time{}, false
+		}
+		index--
+		return s[index], true
+	}
+}
+
 func easter(year int) time.Time {
 	g := year % 19
 	c := year / 100
@@ -142,19 +248,85 @@ func all(next Next) []time.Time {
 	}
 }
 
+// allWithContext is like all, returning the context error if ctx is done before the end.
+func allWithContext(ctx context.Context, next Next) ([]time.Time, error) {
+	result := []time.Time{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		v, ok := next()
+		if !ok {
+			// the iterator may have ended because ctx is done
+			return result, ctx.Err()
+		}
+		result = append(result, v)
+	}
+}
+
 func between(next Next, after, before time.Time, inc bool) []time.Time {
+	return betweenBounds(next, after, before, inc, inc)
+}
+
+func betweenBounds(next Next, after, before time.Time, incStart, incEnd bool) []time.Time {
 	result := []time.Time{}
 	for {
 		v, ok := next()
-		if !ok || inc && v.After(before) || !inc && !v.Before(before) {
+		if !ok || incEnd && v.After(before) || !incEnd && !v.Before(before) {
 			return result
 		}
-		if inc && !v.Before(after) || !inc && v.After(after) {
+		if incStart && !v.Before(after) || !incStart && v.After(after) {
 			result = append(result, v)
 		}
 	}
 }
 
+func betweenLimited(next Next, after, before time.Time, inc bool, max int) ([]time.Time, bool) {
+	// the iteration stops at the first occurrence from after past the limit,
+	// betweenBounds keeps it if it's before before
+	count := 0
+	limited := func() (time.Time, bool) {
+		if count > max {
+			return time.Time{}, false
+		}
+		v, ok := next()
+		if ok && (inc && !v.Before(after) || !inc && v.After(after)) {
+			count++
+		}
+		return v, ok
+	}
+	result := betweenBounds(limited, after, before, inc, inc)
+	if len(result) > max {
+		return result[:max], true
+	}
+	return result, false
+}
+
+func countBetween(next Next, after, before time.Time, inc bool) int {
+	count := 0
+	for {
+		v, ok := next()
+		if !ok || inc && v.After(before) || !inc && !v.Before(before) {
+			return count
+		}
+		if inc && !v.Before(after) || !inc && v.After(after) {
+			count++
+		}
+	}
+}
+
+func forEach(next Next, after, before time.Time, fn func(time.Time) bool) {
+	for {
+		v, ok := next()
+		if !ok || v.After(before) {
+			return
+		}
+		if !v.Before(after) && !fn(v) {
+			return
+		}
+	}
+}
+
 func before(next Next, dt time.Time, inc bool) time.Time {
 	result := time.Time{}
 	for {
@@ -177,3 +349,37 @@ func after(next Next, dt time.Time, inc bool) time.Time {
 		}
 	}
 }
+
+// lastN returns the last n values of next, keeping at most n of them in memory.
+func lastN(next Next, n int) []time.Time {
+	if n <= 0 {
+		return []time.Time{}
+	}
+	ring := make([]time.Time, 0, n)
+	total := 0
+	for {
+		v, ok := next()
+		if !ok {
+			break
+		}
+		if len(ring) < n {
+			ring = append(ring, v)
+		} else {
+			ring[total%n] = v
+		}
+		total++
+	}
+	if len(ring) < n {
+		return ring
+	}
+	start := total % n
+	return append(append([]time.Time{}, ring[start:]...), ring[:start]...)
+}
+
+// inLocation converts the times of ts to loc, in place, and returns ts.
+func inLocation(ts []time.Time, loc *time.Location) []time.Time {
+	for i, t := range ts {
+		ts[i] = t.In(loc)
+	}
+	return ts
+}